@@ -0,0 +1,45 @@
+package makemkv
+
+import "fmt"
+
+// PermissionError is returned before makemkvcon is started when the user
+// can't open a drive's device nodes, which makemkvcon itself only reports as
+// the drive holding no disc.
+type PermissionError struct {
+	Path string
+	// the group owning the node when the user isn't in it
+	Group string
+	// the user was added to Group after this session started
+	NeedsLogin bool
+	Err        error
+}
+
+func (e *PermissionError) Error() string {
+	switch {
+	case e.NeedsLogin:
+		return fmt.Sprintf("cannot open %s: the user is in the %s group but this session isn't, log out and back in", e.Path, e.Group)
+	case e.Group != "":
+		return fmt.Sprintf("cannot open %s: add the user to the %s group", e.Path, e.Group)
+	default:
+		return fmt.Sprintf("cannot open %s: %v", e.Path, e.Err)
+	}
+}
+
+func (e *PermissionError) Unwrap() error {
+	return e.Err
+}
+
+// CheckAccess opens the device nodes of a drive to catch permission problems
+// early, and makes sure a remote image has been mounted. Jobs call it before
+// running makemkvcon, only drives given by their device path are opened.
+func CheckAccess(device Device) error {
+	switch d := device.(type) {
+	case *DevDevice:
+		return checkNodeAccess(d.device)
+	case *RemoteIsoDevice:
+		if d.Device() == "" {
+			return ErrNotMounted
+		}
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package makemkv
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"os/user"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"syscall"
+)
+
+// makemkvcon reads the drive through its SCSI generic node as well, which
+// often has different permissions than /dev/srN
+func checkNodeAccess(name string) error {
+	nodes := []string{"/dev/" + name}
+	if entries, err := os.ReadDir(filepath.Join("/sys/block", name, "device/scsi_generic")); err == nil {
+		for _, entry := range entries {
+			nodes = append(nodes, "/dev/"+entry.Name())
+		}
+	}
+	for _, node := range nodes {
+		if err := openNode(node); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func openNode(path string) error {
+	f, err := os.OpenFile(path, os.O_RDONLY|syscall.O_NONBLOCK, 0)
+	if err == nil {
+		return f.Close()
+	}
+	// anything else, like a missing node, makemkvcon reports well enough
+	if !errors.Is(err, fs.ErrPermission) {
+		return nil
+	}
+
+	perm := &PermissionError{Path: path, Err: err}
+	info, statErr := os.Stat(path)
+	if statErr != nil {
+		return perm
+	}
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok || info.Mode().Perm()&0o040 == 0 {
+		return perm
+	}
+	gid := int(stat.Gid)
+	if groups, err := os.Getgroups(); err != nil || os.Getegid() == gid || slices.Contains(groups, gid) {
+		return perm
+	}
+
+	perm.Group = strconv.Itoa(gid)
+	if group, err := user.LookupGroupId(perm.Group); err == nil {
+		perm.Group = group.Name
+	}
+	if u, err := user.Current(); err == nil {
+		if ids, err := u.GroupIds(); err == nil {
+			perm.NeedsLogin = slices.Contains(ids, strconv.Itoa(gid))
+		}
+	}
+	return perm
+}
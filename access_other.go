@@ -0,0 +1,7 @@
+//go:build !linux
+
+package makemkv
+
+func checkNodeAccess(name string) error {
+	return nil
+}
@@ -0,0 +1,26 @@
+package makemkv
+
+import (
+	"io/fs"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPermissionError(t *testing.T) {
+	err := &PermissionError{Path: "/dev/sg1", Group: "cdrom", Err: fs.ErrPermission}
+	assert.Equal(t, "cannot open /dev/sg1: add the user to the cdrom group", err.Error())
+	assert.ErrorIs(t, err, fs.ErrPermission)
+
+	err.NeedsLogin = true
+	assert.Equal(t, "cannot open /dev/sg1: the user is in the cdrom group but this session isn't, log out and back in", err.Error())
+
+	err = &PermissionError{Path: "/dev/sr0", Err: fs.ErrPermission}
+	assert.Equal(t, "cannot open /dev/sr0: permission denied", err.Error())
+}
+
+func TestCheckAccess(t *testing.T) {
+	assert.NoError(t, CheckAccess(NewIsoDevice("/nonexistent.iso")))
+	// a missing node is left for makemkvcon to report
+	assert.NoError(t, CheckAccess(&DevDevice{device: "nonexistent"}))
+}
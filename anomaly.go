@@ -0,0 +1,115 @@
+package makemkv
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+type AnomalyKind string
+
+const (
+	// many long titles run exactly as long as each other, the usual sign of
+	// decoy playlists
+	AnomalyDuplicateDurations AnomalyKind = "duplicate-durations"
+	// far more playlists than any real disc needs
+	AnomalyPlaylistCount AnomalyKind = "playlist-count"
+	// two scans of the same disc disagree about its titles
+	AnomalyUnstableScan AnomalyKind = "unstable-scan"
+)
+
+type Anomaly struct {
+	Kind   AnomalyKind
+	Detail string
+	// the titles involved, empty when it is about the disc as a whole
+	Titles []int
+}
+
+const (
+	// titles shorter than this are extras, menus and fillers, which often
+	// share lengths legitimately
+	anomalyMinDuration = 20 * time.Minute
+	duplicateThreshold = 5
+	playlistThreshold  = 50
+)
+
+// discAnomalies looks for obfuscation patterns in a single scan
+func discAnomalies(disc DiscInfo) []Anomaly {
+	var anomalies []Anomaly
+	if len(disc.Titles) >= playlistThreshold {
+		anomalies = append(anomalies, Anomaly{
+			Kind:   AnomalyPlaylistCount,
+			Detail: fmt.Sprintf("%d titles", len(disc.Titles)),
+		})
+	}
+
+	byDuration := make(map[time.Duration][]int)
+	for _, title := range disc.Titles {
+		if title.Duration >= anomalyMinDuration {
+			d := title.Duration.Truncate(time.Second)
+			byDuration[d] = append(byDuration[d], title.Id)
+		}
+	}
+	durations := make([]time.Duration, 0, len(byDuration))
+	for d, ids := range byDuration {
+		if len(ids) >= duplicateThreshold {
+			durations = append(durations, d)
+		}
+	}
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+	for _, d := range durations {
+		ids := byDuration[d]
+		anomalies = append(anomalies, Anomaly{
+			Kind:   AnomalyDuplicateDurations,
+			Detail: fmt.Sprintf("%d titles run %s", len(ids), d),
+			Titles: ids,
+		})
+	}
+	return anomalies
+}
+
+// CompareScans reports the titles whose signature differs between two scans
+// of the same disc, which some protections do by shuffling playlists on
+// every read
+func CompareScans(first *DiscInfo, second *DiscInfo) []Anomaly {
+	var ids []int
+	for i := 0; i < max(len(first.Titles), len(second.Titles)); i++ {
+		if i >= len(first.Titles) || i >= len(second.Titles) || Signature(first.Titles[i]) != Signature(second.Titles[i]) {
+			ids = append(ids, i)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	return []Anomaly{{
+		Kind:   AnomalyUnstableScan,
+		Detail: fmt.Sprintf("%d of %d titles changed between scans", len(ids), max(len(first.Titles), len(second.Titles))),
+		Titles: ids,
+	}}
+}
+
+// Recommendation is how to go about picking a title given the anomalies
+// found on a disc
+type Recommendation struct {
+	// how many scans to compare before trusting title ids
+	Scans int
+	// rip a short preview of each candidate to compare by eye or by hash
+	// instead of trusting durations
+	PreviewRips bool
+}
+
+func Recommend(anomalies []Anomaly) Recommendation {
+	rec := Recommendation{Scans: 1}
+	for _, anomaly := range anomalies {
+		switch anomaly.Kind {
+		case AnomalyDuplicateDurations:
+			rec.PreviewRips = true
+		case AnomalyPlaylistCount:
+			rec.Scans = max(rec.Scans, 2)
+		case AnomalyUnstableScan:
+			rec.Scans = max(rec.Scans, 3)
+			rec.PreviewRips = true
+		}
+	}
+	return rec
+}
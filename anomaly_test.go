@@ -0,0 +1,45 @@
+package makemkv
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiscAnomalies(t *testing.T) {
+	var disc DiscInfo
+	for i := 0; i < 60; i++ {
+		title := TitleInfo{Id: i, Duration: time.Duration(i) * time.Minute}
+		if i < 6 {
+			title.Duration = 2*time.Hour + 500*time.Millisecond
+		}
+		disc.Titles = append(disc.Titles, title)
+	}
+	anomalies := discAnomalies(disc)
+	assert.Equal(t, []Anomaly{
+		{Kind: AnomalyPlaylistCount, Detail: "60 titles"},
+		{Kind: AnomalyDuplicateDurations, Detail: "6 titles run 2h0m0s", Titles: []int{0, 1, 2, 3, 4, 5}},
+	}, anomalies)
+	assert.Equal(t, Recommendation{Scans: 2, PreviewRips: true}, Recommend(anomalies))
+
+	assert.Nil(t, discAnomalies(DiscInfo{Titles: disc.Titles[:4]}))
+	assert.Equal(t, Recommendation{Scans: 1}, Recommend(nil))
+}
+
+func TestCompareScans(t *testing.T) {
+	first := &DiscInfo{Titles: []TitleInfo{
+		{Id: 0, Duration: time.Hour, Segments: []int{1, 2}},
+		{Id: 1, Duration: time.Hour, Segments: []int{2, 1}},
+	}}
+	assert.Nil(t, CompareScans(first, first))
+
+	second := &DiscInfo{Titles: []TitleInfo{
+		{Id: 0, Duration: time.Hour, Segments: []int{2, 1}},
+		{Id: 1, Duration: time.Hour, Segments: []int{1, 2}},
+		{Id: 2, Duration: time.Minute},
+	}}
+	anomalies := CompareScans(first, second)
+	assert.Equal(t, []Anomaly{{Kind: AnomalyUnstableScan, Detail: "3 of 3 titles changed between scans", Titles: []int{0, 1, 2}}}, anomalies)
+	assert.Equal(t, Recommendation{Scans: 3, PreviewRips: true}, Recommend(anomalies))
+}
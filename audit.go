@@ -0,0 +1,78 @@
+package makemkv
+
+import (
+	"encoding/json"
+	"io"
+	"os/exec"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+type AuditEntry struct {
+	Args     []string  `json:"args"`
+	Start    time.Time `json:"start"`
+	End      time.Time `json:"end"`
+	ExitCode int       `json:"exit_code"`
+	Error    string    `json:"error,omitempty"`
+}
+
+// AuditLog receives one entry for every makemkvcon invocation once it has
+// finished, whether or not it succeeded.
+type AuditLog interface {
+	Record(entry AuditEntry)
+}
+
+type auditWriter struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+// NewAuditWriter returns an AuditLog that appends entries to w as newline
+// delimited JSON. It is safe to share between jobs.
+func NewAuditWriter(w io.Writer) AuditLog {
+	return &auditWriter{enc: json.NewEncoder(w)}
+}
+
+func (a *auditWriter) Record(entry AuditEntry) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.enc.Encode(entry)
+}
+
+// redacted stands in for arguments kept out of audit logs
+const redacted = "<redacted>"
+
+// auditArgs returns args with the registration key of makemkvcon reg
+// replaced, so it doesn't end up in logs
+func auditArgs(args []string) []string {
+	for i := 1; i < len(args); i++ {
+		if strings.HasPrefix(args[i], "-") {
+			continue
+		}
+		if args[i] != "reg" || i+1 >= len(args) {
+			return args
+		}
+		args = slices.Clone(args)
+		args[i+1] = redacted
+		return args
+	}
+	return args
+}
+
+func auditEntry(cmd *exec.Cmd, start time.Time, err error) AuditEntry {
+	entry := AuditEntry{
+		Args:     auditArgs(cmd.Args),
+		Start:    start,
+		End:      time.Now(),
+		ExitCode: -1,
+	}
+	if cmd.ProcessState != nil {
+		entry.ExitCode = cmd.ProcessState.ExitCode()
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	return entry
+}
@@ -0,0 +1,35 @@
+package makemkv
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditWriter(t *testing.T) {
+	var buf bytes.Buffer
+	audit := NewAuditWriter(&buf)
+	cmd := exec.Command("makemkvcon", "-r", "info", "disc:0")
+	start := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	audit.Record(auditEntry(cmd, start, errors.New("exec: not started")))
+
+	var entry AuditEntry
+	assert.Nil(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, []string{"makemkvcon", "-r", "info", "disc:0"}, entry.Args)
+	assert.True(t, start.Equal(entry.Start))
+	assert.Equal(t, -1, entry.ExitCode)
+	assert.Equal(t, "exec: not started", entry.Error)
+}
+
+func TestAuditArgs(t *testing.T) {
+	args := []string{"makemkvcon", "-r", "--messages=-stdout", "reg", "T-key"}
+	assert.Equal(t, []string{"makemkvcon", "-r", "--messages=-stdout", "reg", redacted}, auditArgs(args))
+	assert.Equal(t, "T-key", args[4], "the command's own args are left alone")
+	info := []string{"makemkvcon", "-r", "info", "reg"}
+	assert.Equal(t, info, auditArgs(info))
+}
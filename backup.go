@@ -0,0 +1,225 @@
+package makemkv
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// BackupJob copies a whole disc into a folder with makemkvcon backup. Set
+// Decrypt in the options for a decrypted copy. Statuschan, Messagechan,
+// Delivery, IncludeRaw, Logger, LogStep and StartTimeout behave as they
+// do on MkvJob.
+type BackupJob struct {
+	Statuschan   chan Status
+	Messagechan  chan Message
+	Delivery     DeliveryPolicy
+	IncludeRaw   bool
+	Thresholds   Thresholds
+	Logger       *slog.Logger
+	LogStep      int
+	StartTimeout time.Duration
+	// Manifest fills in RipResult.HashChecks once the backup is done
+	Manifest    bool
+	device      Device
+	destination string
+	options     MkvOptions
+	stopper     stopper
+}
+
+func Backup(device Device, destination string, opts MkvOptions) *BackupJob {
+	return &BackupJob{
+		device:      device,
+		destination: destination,
+		options:     opts,
+	}
+}
+
+func (j *BackupJob) Run() (*RipResult, error) {
+	return j.RunContext(context.Background())
+}
+
+// RunContext runs the backup, stopping it when ctx is done just like
+// MkvJob.RunContext. HashChecks is only filled in when Manifest is set.
+func (j *BackupJob) RunContext(ctx context.Context) (*RipResult, error) {
+	defer j.stopper.watch(ctx)()
+	if err := CheckAccess(j.device); err != nil {
+		return nil, err
+	}
+	j.options.Power.begin(j.device, j.options)
+	defer j.options.Power.end(j.device)
+	dev := j.device.Type() + ":" + j.device.Device()
+	opts, err := j.options.withProgress(j.Statuschan != nil || j.Logger != nil)
+	if err != nil {
+		return nil, err
+	}
+	opts, file, cleanup, err := opts.withTransport()
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+	cmd := newCommand(opts, "backup", dev, j.destination)
+
+	result, summary, err := runWithProgress(cmd, file, opts.Audit, progress{
+		ch:           j.Statuschan,
+		messages:     j.Messagechan,
+		delivery:     j.Delivery,
+		includeRaw:   j.IncludeRaw,
+		thresholds:   j.Thresholds,
+		stopper:      &j.stopper,
+		logger:       j.Logger,
+		logStep:      j.LogStep,
+		startTimeout: j.StartTimeout,
+	})
+	if j.Manifest {
+		result.HashChecks = hashManifest(j.destination, summary.hashFailures)
+	}
+	if j.Statuschan != nil {
+		result.DroppedStatuses += sendFinal(j.Statuschan, j.Delivery, summary.final)
+	}
+	return result, err
+}
+
+func (j *BackupJob) Stop(reason StopReason) {
+	j.stopper.stop(reason)
+}
+
+var ErrNoBackup = errors.New("makemkv: no disc backup found")
+
+type BackupKind int
+
+const (
+	BackupBluray BackupKind = iota
+	BackupDvd
+)
+
+func (k BackupKind) String() string {
+	switch k {
+	case BackupBluray:
+		return "bluray"
+	case BackupDvd:
+		return "dvd"
+	default:
+		return "unknown"
+	}
+}
+
+type BackupInfo struct {
+	Root string
+	Kind BackupKind
+	// Size adds up the sizes of the files under Root, DiskUsage the space
+	// allocated for them, which block rounding and sparse files make differ
+	Size      int64
+	DiskUsage int64
+	// what is missing from the expected disc structure, empty when it looks whole
+	Problems []string
+}
+
+func (b *BackupInfo) Valid() bool {
+	return len(b.Problems) == 0
+}
+
+// FindBackupRoot returns the first directory at or under dir holding a BDMV
+// or VIDEO_TS folder. dir may also be one of those folders itself.
+func FindBackupRoot(dir string) (string, error) {
+	if isBackupFolder(filepath.Base(dir)) {
+		return filepath.Dir(dir), nil
+	}
+	var root string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && isBackupFolder(d.Name()) {
+			root = filepath.Dir(path)
+			return fs.SkipAll
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	if root == "" {
+		return "", ErrNoBackup
+	}
+	return root, nil
+}
+
+func isBackupFolder(name string) bool {
+	return name == "BDMV" || name == "VIDEO_TS"
+}
+
+// AnalyzeBackup checks the structure of the backup at root and measures it
+func AnalyzeBackup(root string) (BackupInfo, error) {
+	info := BackupInfo{Root: root}
+	if dirExists(filepath.Join(root, "BDMV")) {
+		info.Kind = BackupBluray
+		for _, required := range []string{"index.bdmv", "MovieObject.bdmv", "PLAYLIST", "CLIPINF", "STREAM"} {
+			if !exists(filepath.Join(root, "BDMV", required)) {
+				info.Problems = append(info.Problems, "missing BDMV/"+required)
+			}
+		}
+	} else if dirExists(filepath.Join(root, "VIDEO_TS")) {
+		info.Kind = BackupDvd
+		if !exists(filepath.Join(root, "VIDEO_TS", "VIDEO_TS.IFO")) {
+			info.Problems = append(info.Problems, "missing VIDEO_TS/VIDEO_TS.IFO")
+		}
+		if matches, _ := filepath.Glob(filepath.Join(root, "VIDEO_TS", "VTS_*_0.IFO")); len(matches) == 0 {
+			info.Problems = append(info.Problems, "no title sets in VIDEO_TS")
+		}
+	} else {
+		return info, ErrNoBackup
+	}
+
+	var err error
+	info.Size, info.DiskUsage, err = treeSize(root)
+	return info, err
+}
+
+func treeSize(root string) (size int64, usage int64, err error) {
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.Type().IsRegular() {
+			if fi, err := d.Info(); err == nil {
+				size += fi.Size()
+				usage += allocatedSize(fi)
+			}
+		}
+		return nil
+	})
+	return size, usage, err
+}
+
+// ScanBackup finds the backup under dir, scans it, and attaches what
+// AnalyzeBackup found to the result
+func ScanBackup(dir string, opts MkvOptions) (*DiscInfo, error) {
+	root, err := FindBackupRoot(dir)
+	if err != nil {
+		return nil, err
+	}
+	backup, err := AnalyzeBackup(root)
+	if err != nil {
+		return nil, err
+	}
+	disc, err := Info(NewFileDevice(root), opts).Run()
+	if disc != nil {
+		disc.Backup = &backup
+	}
+	return disc, err
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func dirExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
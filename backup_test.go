@@ -0,0 +1,106 @@
+package makemkv
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindAndAnalyzeBackup(t *testing.T) {
+	parent := t.TempDir()
+	root := filepath.Join(parent, "Movies", "MOVIE_1")
+	bdmv := filepath.Join(root, "BDMV")
+	for _, dir := range []string{"PLAYLIST", "CLIPINF", "STREAM"} {
+		assert.Nil(t, os.MkdirAll(filepath.Join(bdmv, dir), 0o755))
+	}
+	assert.Nil(t, os.WriteFile(filepath.Join(bdmv, "index.bdmv"), make([]byte, 10), 0o644))
+	assert.Nil(t, os.WriteFile(filepath.Join(bdmv, "STREAM", "00000.m2ts"), make([]byte, 100), 0o644))
+
+	found, err := FindBackupRoot(parent)
+	assert.Nil(t, err)
+	assert.Equal(t, root, found)
+	found, err = FindBackupRoot(bdmv)
+	assert.Nil(t, err)
+	assert.Equal(t, root, found)
+
+	info, err := AnalyzeBackup(root)
+	assert.Nil(t, err)
+	assert.Equal(t, BackupBluray, info.Kind)
+	assert.Equal(t, int64(110), info.Size)
+	// whole blocks are allocated for the two small files
+	assert.GreaterOrEqual(t, info.DiskUsage, info.Size)
+	assert.False(t, info.Valid())
+	assert.Equal(t, []string{"missing BDMV/MovieObject.bdmv"}, info.Problems)
+
+	_, err = FindBackupRoot(t.TempDir())
+	assert.ErrorIs(t, err, ErrNoBackup)
+	_, err = AnalyzeBackup(parent)
+	assert.ErrorIs(t, err, ErrNoBackup)
+}
+
+func TestAnalyzeDvdBackup(t *testing.T) {
+	root := t.TempDir()
+	videoTs := filepath.Join(root, "VIDEO_TS")
+	assert.Nil(t, os.Mkdir(videoTs, 0o755))
+	for _, name := range []string{"VIDEO_TS.IFO", "VTS_01_0.IFO"} {
+		assert.Nil(t, os.WriteFile(filepath.Join(videoTs, name), nil, 0o644))
+	}
+
+	info, err := AnalyzeBackup(root)
+	assert.Nil(t, err)
+	assert.Equal(t, BackupDvd, info.Kind)
+	assert.True(t, info.Valid())
+}
+
+func TestFakeBackup(t *testing.T) {
+	const banner = `MSG:1005,0,1,"MakeMKV v1.17.6 linux(x64-release) started","%1 started","MakeMKV v1.17.6 linux(x64-release)"
+PRGT:5018,0,"Saving all titles to MKV files"
+PRGV:65536,65536,65536
+`
+	const hashFailed = `MSG:5033,0,2,"Hash check failed for file /BDMV/STREAM/00001.m2ts at offset 0, file is corrupt","Hash check failed for file %1 at offset %2, file is corrupt","/BDMV/STREAM/00001.m2ts","0"
+`
+	// the fake doesn't write anything, so the backup is laid out up front
+	destination := func() string {
+		dir := t.TempDir()
+		stream := filepath.Join(dir, "BDMV", "STREAM")
+		if err := os.MkdirAll(stream, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		for _, name := range []string{"00000.m2ts", "00001.m2ts"} {
+			if err := os.WriteFile(filepath.Join(stream, name), []byte("data"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return dir
+	}
+
+	job := Backup(NewDiscDevice(0), destination(), fakeMakemkvcon(t, banner, 0))
+	job.Manifest = true
+	result, err := job.RunContext(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, OutcomeSuccess, result.Outcome)
+	assert.Equal(t, []HashCheck{{File: "BDMV/STREAM/00000.m2ts"}, {File: "BDMV/STREAM/00001.m2ts"}}, result.HashChecks)
+
+	job = Backup(NewDiscDevice(0), destination(), fakeMakemkvcon(t, banner+`MSG:5010,0,0,"Failed to open disc","Failed to open disc"
+`, 1))
+	result, err = job.RunContext(context.Background())
+	assert.ErrorIs(t, err, ErrDiscOpen)
+	assert.Equal(t, OutcomeFatal, result.Outcome)
+	assert.Nil(t, result.HashChecks)
+
+	job = Backup(NewDiscDevice(0), destination(), fakeMakemkvcon(t, banner+hashFailed, 0))
+	job.Manifest = true
+	result, err = job.RunContext(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, []HashCheck{{File: "BDMV/STREAM/00000.m2ts"}, {File: "BDMV/STREAM/00001.m2ts", Failures: 1}}, result.HashChecks)
+
+	// and blamed for a failed backup
+	job = Backup(NewDiscDevice(0), destination(), fakeMakemkvcon(t, banner+hashFailed, 1))
+	job.Manifest = true
+	result, err = job.RunContext(context.Background())
+	assert.ErrorIs(t, err, ErrHashCheck)
+	assert.False(t, result.HashChecks[1].Ok())
+}
@@ -0,0 +1,87 @@
+package makemkv
+
+import (
+	"fmt"
+	"io/fs"
+)
+
+// CompatProblem is an invariant a parsed fixture broke, TitleId is -1 for
+// problems with the disc as a whole
+type CompatProblem struct {
+	File    string
+	TitleId int
+	Problem string
+}
+
+func (p CompatProblem) String() string {
+	if p.TitleId < 0 {
+		return fmt.Sprintf("%s: %s", p.File, p.Problem)
+	}
+	return fmt.Sprintf("%s: title %d: %s", p.File, p.TitleId, p.Problem)
+}
+
+// CheckCorpus parses every file in fsys as captured makemkvcon info output
+// and checks what any real disc should satisfy. Pointed at output saved
+// from the discs an application cares about, it lets CI catch an upgrade of
+// this package parsing them differently. The error is only for failing to
+// read fsys.
+func CheckCorpus(fsys fs.FS) ([]CompatProblem, error) {
+	var problems []CompatProblem
+	err := fs.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		f, err := fsys.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		disc, err := ParseDiscInfo(f)
+		if err != nil {
+			return err
+		}
+		for _, problem := range checkDisc(disc) {
+			problem.File = path
+			problems = append(problems, problem)
+		}
+		return nil
+	})
+	return problems, err
+}
+
+func checkDisc(disc *DiscInfo) []CompatProblem {
+	var problems []CompatProblem
+	problem := func(titleId int, format string, args ...any) {
+		problems = append(problems, CompatProblem{TitleId: titleId, Problem: fmt.Sprintf(format, args...)})
+	}
+
+	if disc.Name == "" {
+		problem(-1, "disc has no name")
+	}
+	if len(disc.Titles) == 0 {
+		problem(-1, "no titles")
+	}
+	for _, title := range disc.Titles {
+		if len(title.RawAttrs) == 0 {
+			problem(title.Id, "no attributes")
+			continue
+		}
+		if title.Name == "" {
+			problem(title.Id, "no name")
+		}
+		if title.Duration <= 0 {
+			problem(title.Id, "no duration")
+		}
+		if len(title.VideoStreams) == 0 {
+			problem(title.Id, "no video stream")
+		}
+		ids := make(map[int]bool)
+		for _, stream := range title.Streams() {
+			if ids[stream.StreamId()] {
+				problem(title.Id, "stream %d appears twice", stream.StreamId())
+			}
+			ids[stream.StreamId()] = true
+		}
+	}
+	return problems
+}
@@ -0,0 +1,23 @@
+package makemkv
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckCorpus(t *testing.T) {
+	problems, err := CheckCorpus(fstest.MapFS{
+		"bluray.txt": {Data: []byte(input)},
+		"broken.txt": {Data: []byte("TCOUNT:2\nTINFO:0,2,0,\"Title\"\nSINFO:0,0,1,6202,\"Audio\"\nSINFO:0,0,1,6202,\"Audio\"\n")},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []CompatProblem{
+		{File: "broken.txt", TitleId: -1, Problem: "disc has no name"},
+		{File: "broken.txt", TitleId: 0, Problem: "no duration"},
+		{File: "broken.txt", TitleId: 0, Problem: "no video stream"},
+		{File: "broken.txt", TitleId: 1, Problem: "no attributes"},
+	}, problems)
+	assert.Equal(t, "broken.txt: title 1: no attributes", problems[3].String())
+}
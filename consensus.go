@@ -0,0 +1,93 @@
+package makemkv
+
+import "slices"
+
+// Consensus combines several scans of a disc whose playlists may change from
+// one scan to the next. Titles only count as stable when a title with the
+// same signature turned up in every scan, whatever its id.
+type Consensus struct {
+	// a copy of the last scan, with any differences between scans added to
+	// its Anomalies
+	Disc  *DiscInfo
+	Scans int
+	// ids in Disc of the stable titles, ascending
+	Stable []int
+}
+
+// ScanConsensus scans device n times, stopping at the first failed scan
+func ScanConsensus(device Device, n int, opts MkvOptions) (*Consensus, error) {
+	scans := make([]*DiscInfo, 0, n)
+	for i := 0; i < n; i++ {
+		disc, err := Info(device, opts).Run()
+		if err != nil {
+			return nil, err
+		}
+		scans = append(scans, disc)
+	}
+	return NewConsensus(scans), nil
+}
+
+func NewConsensus(scans []*DiscInfo) *Consensus {
+	if len(scans) == 0 {
+		return &Consensus{}
+	}
+	disc := *scans[len(scans)-1]
+	disc.Anomalies = slices.Clone(disc.Anomalies)
+	last := &disc
+	c := &Consensus{Disc: last, Scans: len(scans)}
+
+	seen := make(map[TitleSignature]int)
+	for _, scan := range scans {
+		inScan := make(map[TitleSignature]bool)
+		for _, title := range scan.Titles {
+			inScan[Signature(title)] = true
+		}
+		for sig := range inScan {
+			seen[sig]++
+		}
+	}
+	for _, title := range last.Titles {
+		if seen[Signature(title)] == len(scans) {
+			c.Stable = append(c.Stable, title.Id)
+		}
+	}
+	for _, scan := range scans[:len(scans)-1] {
+		last.Anomalies = append(last.Anomalies, CompareScans(scan, last)...)
+	}
+	return c
+}
+
+func (c *Consensus) IsStable(titleId int) bool {
+	_, found := slices.BinarySearch(c.Stable, titleId)
+	return found
+}
+
+// StableTitles returns the stable titles in disc order
+func (c *Consensus) StableTitles() []*TitleInfo {
+	var titles []*TitleInfo
+	if c.Disc == nil {
+		return nil
+	}
+	for i := range c.Disc.Titles {
+		if c.IsStable(c.Disc.Titles[i].Id) {
+			titles = append(titles, &c.Disc.Titles[i])
+		}
+	}
+	return titles
+}
+
+// MainFeature picks the one stable title meeting expect without warnings,
+// giving up when none or several do
+func (c *Consensus) MainFeature(expect TitleExpectations) (*TitleInfo, bool) {
+	var found *TitleInfo
+	for _, title := range c.StableTitles() {
+		if len(CheckTitle(*title, expect)) > 0 {
+			continue
+		}
+		if found != nil {
+			return nil, false
+		}
+		found = title
+	}
+	return found, found != nil
+}
@@ -0,0 +1,51 @@
+package makemkv
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConsensus(t *testing.T) {
+	feature := TitleInfo{Duration: 2 * time.Hour, ChapterCount: 24, Segments: []int{1, 2, 3}}
+	decoy := func(id int, segments ...int) TitleInfo {
+		return TitleInfo{Id: id, Duration: 2 * time.Hour, ChapterCount: 24, Segments: segments}
+	}
+	extra := TitleInfo{Duration: 10 * time.Minute, ChapterCount: 1}
+
+	first := feature
+	first.Id = 0
+	last := feature
+	last.Id = 2
+	extra0, extra2 := extra, extra
+	extra0.Id, extra2.Id = 2, 0
+	scans := []*DiscInfo{
+		{Titles: []TitleInfo{first, decoy(1, 3, 2, 1), extra0}},
+		{Titles: []TitleInfo{extra2, decoy(1, 2, 1, 3), last}},
+	}
+
+	c := NewConsensus(scans)
+	assert.Equal(t, 2, c.Scans)
+	assert.Equal(t, []int{0, 2}, c.Stable)
+	assert.False(t, c.IsStable(1))
+	if title, ok := c.MainFeature(MainFeatureExpectations); assert.True(t, ok) {
+		assert.Equal(t, 2, title.Id)
+	}
+	if assert.Equal(t, 1, len(c.Disc.Anomalies)) {
+		assert.Equal(t, AnomalyUnstableScan, c.Disc.Anomalies[0].Kind)
+	}
+	assert.Empty(t, scans[1].Anomalies, "the scans passed in are left alone")
+
+	// without the scans to tell them apart neither playlist is picked
+	single := NewConsensus([]*DiscInfo{{Titles: []TitleInfo{decoy(0, 1, 2, 3), decoy(1, 3, 2, 1)}}})
+	_, ok := single.MainFeature(MainFeatureExpectations)
+	assert.False(t, ok)
+}
+
+func TestConsensusEmpty(t *testing.T) {
+	c := NewConsensus(nil)
+	assert.Nil(t, c.StableTitles())
+	_, ok := c.MainFeature(MainFeatureExpectations)
+	assert.False(t, ok)
+}
@@ -0,0 +1,100 @@
+package makemkv
+
+import "time"
+
+// repeatInterval is how often a message that keeps repeating is passed on
+// again, with how often it has been printed so far
+const repeatInterval = 5 * time.Second
+
+// MessageCount is a message makemkvcon printed over and over, like read
+// retries on a damaged disc, collapsed into one entry
+type MessageCount struct {
+	Code int
+	// the text of the first occurrence
+	Text  string
+	Count int
+}
+
+// messageCounter groups messages by code and format, so repeats that only
+// differ in their parameters (sector numbers and the like) count together
+type messageCounter struct {
+	index  map[messageKey]int
+	counts []MessageCount
+	// what was last passed on live for each count
+	sent []sentRepeat
+}
+
+type sentRepeat struct {
+	count int
+	at    time.Time
+	// the latest occurrence, for the update at the end of a job
+	last Message
+}
+
+type messageKey struct {
+	code   int
+	format string
+}
+
+func (c *messageCounter) observe(msg Message) {
+	key := messageKey{msg.Code, msg.Format}
+	if i, ok := c.index[key]; ok {
+		c.counts[i].Count++
+		return
+	}
+	if c.index == nil {
+		c.index = make(map[messageKey]int)
+	}
+	c.index[key] = len(c.counts)
+	c.counts = append(c.counts, MessageCount{Code: msg.Code, Text: msg.Text, Count: 1})
+}
+
+// live decides what consumers watching a job see of msg, which was just
+// observed. The first occurrence is passed on as it is. Repeats are held back
+// and passed on at most every interval as msg with Count set, later ones
+// being left to pending.
+func (c *messageCounter) live(msg Message, now time.Time, interval time.Duration) (Message, bool) {
+	i := c.index[messageKey{msg.Code, msg.Format}]
+	for len(c.sent) <= i {
+		c.sent = append(c.sent, sentRepeat{})
+	}
+	sent := &c.sent[i]
+	sent.last = msg
+	count := c.counts[i].Count
+	if count == 1 {
+		sent.count, sent.at = 1, now
+		return msg, true
+	}
+	if now.Sub(sent.at) < interval {
+		return Message{}, false
+	}
+	sent.count, sent.at = count, now
+	msg.Count = count
+	return msg, true
+}
+
+// pending returns an update for each repeated message whose latest count
+// hasn't been passed on, for the end of a job
+func (c *messageCounter) pending() []Message {
+	var updates []Message
+	for i, sent := range c.sent {
+		if count := c.counts[i].Count; count > sent.count {
+			msg := sent.last
+			msg.Count = count
+			updates = append(updates, msg)
+		}
+	}
+	return updates
+}
+
+// repeated returns the messages seen more than once, in order of first
+// appearance
+func (c *messageCounter) repeated() []MessageCount {
+	var repeated []MessageCount
+	for _, count := range c.counts {
+		if count.Count > 1 {
+			repeated = append(repeated, count)
+		}
+	}
+	return repeated
+}
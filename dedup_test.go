@@ -0,0 +1,61 @@
+package makemkv
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMessageCounter(t *testing.T) {
+	var counter messageCounter
+	for _, line := range []string{
+		`2003,0,3,"Error 'Scsi error' occurred while reading '/BDMV/STREAM/00001.m2ts' at offset '1048576'","Error '%1' occurred while reading '%2' at offset '%3'","Scsi error","/BDMV/STREAM/00001.m2ts","1048576"`,
+		`5011,0,0,"Operation successfully completed","Operation successfully completed"`,
+		`2003,0,3,"Error 'Scsi error' occurred while reading '/BDMV/STREAM/00001.m2ts' at offset '2097152'","Error '%1' occurred while reading '%2' at offset '%3'","Scsi error","/BDMV/STREAM/00001.m2ts","2097152"`,
+		`2003,0,3,"Error 'Scsi error' occurred while reading '/BDMV/STREAM/00001.m2ts' at offset '3145728'","Error '%1' occurred while reading '%2' at offset '%3'","Scsi error","/BDMV/STREAM/00001.m2ts","3145728"`,
+	} {
+		msg, ok := parseMessage(line)
+		assert.True(t, ok)
+		counter.observe(msg)
+	}
+	assert.Equal(t, []MessageCount{{
+		Code:  2003,
+		Text:  "Error 'Scsi error' occurred while reading '/BDMV/STREAM/00001.m2ts' at offset '1048576'",
+		Count: 3,
+	}}, counter.repeated())
+}
+
+func TestMessageCounterLive(t *testing.T) {
+	var counter messageCounter
+	retry := func(offset string) Message {
+		return Message{Code: 2003, Text: "Error at offset " + offset, Format: "Error at offset %1", Params: []string{offset}}
+	}
+	start := time.Now()
+	var live []Message
+	for i, offset := range []string{"1", "2", "3", "4", "5"} {
+		msg := retry(offset)
+		counter.observe(msg)
+		if msg, ok := counter.live(msg, start.Add(time.Duration(i)*time.Second), 2*time.Second); ok {
+			live = append(live, msg)
+		}
+	}
+	other := Message{Code: 5011, Text: "Operation successfully completed", Format: "Operation successfully completed"}
+	counter.observe(other)
+	_, ok := counter.live(other, start.Add(5*time.Second), 2*time.Second)
+	assert.True(t, ok)
+
+	first := retry("1")
+	update := retry("3")
+	update.Count = 3
+	again := retry("5")
+	again.Count = 5
+	assert.Equal(t, []Message{first, update, again}, live)
+	assert.Empty(t, counter.pending(), "the last repeat was passed on")
+
+	counter.observe(retry("6"))
+	counter.live(retry("6"), start.Add(5*time.Second), 2*time.Second)
+	last := retry("6")
+	last.Count = 6
+	assert.Equal(t, []Message{last}, counter.pending())
+}
@@ -0,0 +1,69 @@
+package makemkv
+
+type DeliveryPolicy int
+
+const (
+	// DeliverBlocking waits for the consumer on every status, so nothing is
+	// lost but a slow consumer stalls output parsing.
+	DeliverBlocking DeliveryPolicy = iota
+	// DeliverLatest never blocks; when the channel is full the oldest queued
+	// status is replaced with the newest one.
+	DeliverLatest
+	// DeliverDrop never blocks; when the channel is full the new status is
+	// discarded and counted.
+	DeliverDrop
+)
+
+type statusSender struct {
+	ch      chan Status
+	policy  DeliveryPolicy
+	dropped uint64
+}
+
+func (s *statusSender) send(status Status) {
+	if s.ch == nil {
+		return
+	}
+	policy := s.policy
+	if policy == DeliverLatest && cap(s.ch) == 0 {
+		// there is no queued status to replace on an unbuffered channel
+		policy = DeliverDrop
+	}
+	switch policy {
+	case DeliverLatest:
+		for {
+			select {
+			case s.ch <- status:
+				return
+			default:
+			}
+			select {
+			case <-s.ch:
+				s.dropped++
+			default:
+			}
+		}
+	case DeliverDrop:
+		select {
+		case s.ch <- status:
+		default:
+			s.dropped++
+		}
+	default:
+		s.ch <- status
+	}
+}
+
+// final sends the last status of a job. It is never discarded: DeliverDrop
+// replaces the oldest queued status instead, and on an unbuffered channel,
+// where there is nothing queued to replace, the send blocks.
+func (s *statusSender) final(status Status) {
+	if s.ch != nil && cap(s.ch) == 0 {
+		s.ch <- status
+		return
+	}
+	if s.policy == DeliverDrop {
+		s.policy = DeliverLatest
+	}
+	s.send(status)
+}
@@ -0,0 +1,44 @@
+package makemkv
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatusSenderLatest(t *testing.T) {
+	sender := statusSender{ch: make(chan Status, 2), policy: DeliverLatest}
+	for i := 1; i <= 5; i++ {
+		sender.send(Status{Seq: uint64(i)})
+	}
+	assert.Equal(t, uint64(3), sender.dropped)
+	assert.Equal(t, uint64(4), (<-sender.ch).Seq)
+	assert.Equal(t, uint64(5), (<-sender.ch).Seq)
+}
+
+func TestStatusSenderDrop(t *testing.T) {
+	sender := statusSender{ch: make(chan Status, 2), policy: DeliverDrop}
+	for i := 1; i <= 5; i++ {
+		sender.send(Status{Seq: uint64(i)})
+	}
+	assert.Equal(t, uint64(3), sender.dropped)
+	assert.Equal(t, uint64(1), (<-sender.ch).Seq)
+	assert.Equal(t, uint64(2), (<-sender.ch).Seq)
+
+	unbuffered := statusSender{ch: make(chan Status), policy: DeliverLatest}
+	unbuffered.send(Status{Seq: 1})
+	assert.Equal(t, uint64(1), unbuffered.dropped)
+}
+
+func TestStatusSenderFinalUnbuffered(t *testing.T) {
+	for _, policy := range []DeliveryPolicy{DeliverBlocking, DeliverLatest, DeliverDrop} {
+		sender := statusSender{ch: make(chan Status), policy: policy}
+		done := make(chan Status)
+		go func() {
+			done <- <-sender.ch
+		}()
+		sender.final(Status{Seq: 7, Final: true})
+		assert.Equal(t, Status{Seq: 7, Final: true}, <-done, policy)
+		assert.Equal(t, uint64(0), sender.dropped, policy)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"io/fs"
 	"os"
 	"strconv"
+	"strings"
 )
 
 type Device interface {
@@ -13,10 +14,51 @@ type Device interface {
 	Available() bool
 }
 
+// Capable is implemented by devices that know what they support, others get
+// the defaults for their Type
+type Capable interface {
+	Capabilities() Capabilities
+}
+
+// Capabilities says which operations make sense for a device, for callers
+// that need to enable or disable actions without knowing the device type
+type Capabilities struct {
+	// has a tray that can be opened
+	Eject bool
+	// is a physical drive whose read speed can be queried
+	DriveSpeed bool
+	// can be served with makemkvcon stream
+	Stream bool
+	// can be copied with makemkvcon backup
+	Backup bool
+}
+
+var (
+	imageCapabilities = Capabilities{Stream: true, Backup: true}
+	driveCapabilities = Capabilities{Eject: true, DriveSpeed: true, Stream: true, Backup: true}
+)
+
+// DeviceCapabilities asks device what it supports when it is Capable, dev
+// and disc devices are otherwise taken to be drives and the rest images
+func DeviceCapabilities(device Device) Capabilities {
+	if c, ok := device.(Capable); ok {
+		return c.Capabilities()
+	}
+	switch device.Type() {
+	case "dev", "disc":
+		return driveCapabilities
+	}
+	return imageCapabilities
+}
+
 type IsoDevice struct {
 	path string
 }
 
+func NewIsoDevice(path string) *IsoDevice {
+	return &IsoDevice{path: path}
+}
+
 func (d *IsoDevice) Device() string {
 	return d.path
 }
@@ -25,6 +67,10 @@ func (d *IsoDevice) Type() string {
 	return "iso"
 }
 
+func (d *IsoDevice) Capabilities() Capabilities {
+	return imageCapabilities
+}
+
 func (d *IsoDevice) Available() bool {
 	info, err := os.Stat(d.path)
 	return err == nil && !info.IsDir()
@@ -34,6 +80,11 @@ type FileDevice struct {
 	path string
 }
 
+// NewFileDevice is a device for a decrypted backup folder
+func NewFileDevice(path string) *FileDevice {
+	return &FileDevice{path: path}
+}
+
 func (d *FileDevice) Device() string {
 	return d.path
 }
@@ -42,6 +93,10 @@ func (d *FileDevice) Type() string {
 	return "file"
 }
 
+func (d *FileDevice) Capabilities() Capabilities {
+	return imageCapabilities
+}
+
 func (d *FileDevice) Available() bool {
 	info, err := os.Stat(d.path)
 	return err == nil && info.IsDir()
@@ -51,12 +106,22 @@ type DevDevice struct {
 	device string
 }
 
+// NewDevDevice is a device for a drive's node, named either like sr0 or
+// /dev/sr0
+func NewDevDevice(device string) *DevDevice {
+	return &DevDevice{device: strings.TrimPrefix(device, "/dev/")}
+}
+
 func (d *DevDevice) Device() string {
 	return "/dev/" + d.device
 }
 
 func (d *DevDevice) Type() string {
-	return "disc"
+	return "dev"
+}
+
+func (d *DevDevice) Capabilities() Capabilities {
+	return driveCapabilities
 }
 
 func (d *DevDevice) Available() bool {
@@ -66,6 +131,20 @@ func (d *DevDevice) Available() bool {
 
 type DiscDevice struct {
 	id int
+	// Audit, Binary and Env for listing drives in Available
+	opts MkvOptions
+}
+
+// NewDiscDevice is a device for the drive makemkvcon lists at index id
+func NewDiscDevice(id int) *DiscDevice {
+	return &DiscDevice{id: id}
+}
+
+// WithOptions returns a copy of the device that runs makemkvcon with the
+// Audit, Binary and Env of opts when checking it is Available, pass the
+// options the job uses so both run the same makemkvcon
+func (d *DiscDevice) WithOptions(opts MkvOptions) *DiscDevice {
+	return &DiscDevice{id: d.id, opts: MkvOptions{Audit: opts.Audit, Binary: opts.Binary, Env: opts.Env}}
 }
 
 func (d *DiscDevice) Device() string {
@@ -73,9 +152,24 @@ func (d *DiscDevice) Device() string {
 }
 
 func (d *DiscDevice) Type() string {
-	return "dev"
+	return "disc"
+}
+
+func (d *DiscDevice) Capabilities() Capabilities {
+	return driveCapabilities
 }
 
+// Available lists the drives to check that one is present at the index,
+// it doesn't need to hold a disc
 func (d *DiscDevice) Available() bool {
-	panic("not yet implemented")
+	drives, err := ListDrives(d.opts)
+	if err != nil {
+		return false
+	}
+	for _, drive := range drives {
+		if drive.Index == d.id {
+			return drive.Present()
+		}
+	}
+	return false
 }
@@ -0,0 +1,50 @@
+package makemkv
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeviceCapabilities(t *testing.T) {
+	for _, device := range []Device{&IsoDevice{}, &FileDevice{}} {
+		caps := DeviceCapabilities(device)
+		assert.False(t, caps.Eject, device.Type())
+		assert.False(t, caps.DriveSpeed, device.Type())
+		assert.True(t, caps.Stream, device.Type())
+	}
+	for _, device := range []Device{&DevDevice{}, &DiscDevice{}} {
+		caps := DeviceCapabilities(device)
+		assert.True(t, caps.Eject, device.Type())
+		assert.True(t, caps.DriveSpeed, device.Type())
+		assert.True(t, caps.Stream, device.Type())
+	}
+
+	assert.Equal(t, driveCapabilities, DeviceCapabilities(plainDevice{"dev"}))
+	assert.Equal(t, imageCapabilities, DeviceCapabilities(plainDevice{"iso"}))
+}
+
+// plainDevice doesn't implement Capable
+type plainDevice struct {
+	kind string
+}
+
+func (d plainDevice) Device() string  { return "plain" }
+func (d plainDevice) Type() string    { return d.kind }
+func (d plainDevice) Available() bool { return true }
+
+func TestNewDevDevice(t *testing.T) {
+	assert.Equal(t, "/dev/sr0", NewDevDevice("sr0").Device())
+	assert.Equal(t, "/dev/sr0", NewDevDevice("/dev/sr0").Device())
+}
+
+func TestDiscDeviceAvailable(t *testing.T) {
+	opts := fakeMakemkvcon(t, `DRV:0,2,999,12,"BD-RE HL-DT-ST BD-RE  WH16NS60 1.02","MOVIE","/dev/sr0"
+DRV:1,0,999,0,"DVD+R-DL ASUS DRW-24F1ST","","/dev/sr1"
+DRV:2,256,999,0,"","",""
+`, 0)
+	assert.True(t, NewDiscDevice(0).WithOptions(opts).Available())
+	assert.True(t, NewDiscDevice(1).WithOptions(opts).Available(), "empty drives are still there")
+	assert.False(t, NewDiscDevice(2).WithOptions(opts).Available())
+	assert.False(t, NewDiscDevice(3).WithOptions(opts).Available())
+}
@@ -0,0 +1,107 @@
+package makemkv
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// drive states makemkvcon reports in the second DRV field
+const (
+	driveInserted = 2
+	driveNone     = 256
+)
+
+// DriveInfo is a DRV line:
+// DRV:index,visible,enabled,flags,"drive name","disc name","device path"
+type DriveInfo struct {
+	Index int
+	// the drive's state: 0 empty, 1 tray open, 2 disc inserted, 3 loading,
+	// 256 for an index with no drive behind it
+	Visible    int
+	Enabled    int
+	Flags      int
+	DriveName  string
+	DiscName   string
+	DevicePath string
+}
+
+func (d DriveInfo) Present() bool {
+	return d.Visible != driveNone
+}
+
+func (d DriveInfo) HasDisc() bool {
+	return d.Visible == driveInserted
+}
+
+func (d DriveInfo) Device() *DiscDevice {
+	return NewDiscDevice(d.Index)
+}
+
+// ListDrives asks makemkvcon for every drive slot it knows about, including
+// empty ones; filter on Present to get actual drives
+func ListDrives(opts MkvOptions) ([]DriveInfo, error) {
+	opts, err := opts.withProgress(false)
+	if err != nil {
+		return nil, err
+	}
+	opts, file, cleanup, err := opts.withTransport()
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+	// 9999 is never a real drive, so makemkvcon lists the drives and stops
+	cmd := newCommand(opts, "info", "disc:9999")
+
+	var drives []DriveInfo
+	var s stopper
+	parseErr, err := runCommand(cmd, &s, file, opts.Audit, func(out io.Reader) error {
+		var err error
+		drives, err = parseDrives(bufio.NewScanner(out))
+		return err
+	})
+	if parseErr != nil {
+		return nil, parseErr
+	}
+	// failing to open the made up disc can show up in the exit status, which
+	// doesn't matter once the drives have been listed
+	if err != nil && len(drives) == 0 {
+		return nil, err
+	}
+	return drives, nil
+}
+
+func parseDrives(scanner *bufio.Scanner) ([]DriveInfo, error) {
+	var drives []DriveInfo
+	for scanner.Scan() {
+		content, found := strings.CutPrefix(scanner.Text(), "DRV:")
+		if !found {
+			continue
+		}
+		if drive, ok := parseDrive(content, protocolFor(Version{})); ok {
+			drives = append(drives, drive)
+		}
+	}
+	return drives, scanner.Err()
+}
+
+// parseDrive reads a DRV line's content as printed by a release with proto
+func parseDrive(content string, proto protocol) (DriveInfo, bool) {
+	fields := splitQuoted(content)
+	if len(fields) < proto.drvFields {
+		return DriveInfo{}, false
+	}
+	var drive DriveInfo
+	var err error
+	if drive.Index, err = strconv.Atoi(fields[0]); err != nil {
+		return DriveInfo{}, false
+	}
+	drive.Visible, _ = strconv.Atoi(fields[1])
+	drive.Enabled, _ = strconv.Atoi(fields[2])
+	drive.Flags, _ = strconv.Atoi(fields[3])
+	drive.DriveName = fields[4]
+	drive.DiscName = fields[5]
+	drive.DevicePath = fields[6]
+	return drive, true
+}
@@ -0,0 +1,31 @@
+package makemkv
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDrives(t *testing.T) {
+	scanner := bufio.NewScanner(strings.NewReader(`MSG:1005,0,1,"MakeMKV v1.17.6 linux(x64-release) started","%1 started","MakeMKV v1.17.6 linux(x64-release)"
+DRV:0,2,999,12,"BD-RE HL-DT-ST BD-RE  WH16NS60 1.02","MOVIE, THE","/dev/sr0"
+DRV:1,0,999,0,"DVD+R-DL ASUS DRW-24F1ST","","/dev/sr1"
+DRV:2,256,999,0,"","",""
+MSG:5010,0,0,"Failed to open disc","Failed to open disc"
+`))
+	drives, err := parseDrives(scanner)
+	assert.Nil(t, err)
+	assert.Equal(t, []DriveInfo{
+		{Index: 0, Visible: 2, Enabled: 999, Flags: 12, DriveName: "BD-RE HL-DT-ST BD-RE  WH16NS60 1.02", DiscName: "MOVIE, THE", DevicePath: "/dev/sr0"},
+		{Index: 1, Visible: 0, Enabled: 999, DriveName: "DVD+R-DL ASUS DRW-24F1ST", DevicePath: "/dev/sr1"},
+		{Index: 2, Visible: 256, Enabled: 999},
+	}, drives)
+	assert.True(t, drives[0].HasDisc())
+	assert.True(t, drives[1].Present())
+	assert.False(t, drives[1].HasDisc())
+	assert.False(t, drives[2].Present())
+	assert.Equal(t, "disc", drives[0].Device().Type())
+	assert.Equal(t, "0", drives[0].Device().Device())
+}
@@ -0,0 +1,82 @@
+package makemkv
+
+import (
+	"sort"
+	"time"
+)
+
+type EpisodeMatch struct {
+	TitleId int
+	Episode int
+}
+
+type EpisodeMapping struct {
+	Episodes   []EpisodeMatch
+	Confidence float64
+}
+
+// MatchEpisodes looks for the count titles whose durations and chapter counts
+// look most like a run of episodes, and numbers them in playlist order.
+// Confidence is in [0, 1] and drops as durations spread apart and chapter
+// counts disagree.
+func MatchEpisodes(disc DiscInfo, count int) (EpisodeMapping, bool) {
+	if count <= 0 || len(disc.Titles) < count {
+		return EpisodeMapping{}, false
+	}
+
+	titles := make([]TitleInfo, len(disc.Titles))
+	copy(titles, disc.Titles)
+	sort.SliceStable(titles, func(a, b int) bool {
+		return titles[a].Duration < titles[b].Duration
+	})
+
+	// slide a window over the titles ordered by duration and keep the tightest
+	best := -1
+	var bestScore float64
+	for i := 0; i+count <= len(titles); i++ {
+		window := titles[i : i+count]
+		score := durationScore(window) * chapterScore(window)
+		if best < 0 || score > bestScore {
+			best, bestScore = i, score
+		}
+	}
+
+	episodes := make([]TitleInfo, count)
+	copy(episodes, titles[best:best+count])
+	sort.SliceStable(episodes, func(a, b int) bool {
+		if episodes[a].SourceFileName != episodes[b].SourceFileName {
+			return episodes[a].SourceFileName < episodes[b].SourceFileName
+		}
+		return episodes[a].Id < episodes[b].Id
+	})
+
+	mapping := EpisodeMapping{
+		Episodes:   make([]EpisodeMatch, count),
+		Confidence: bestScore,
+	}
+	for i, title := range episodes {
+		mapping.Episodes[i] = EpisodeMatch{TitleId: title.Id, Episode: i + 1}
+	}
+	return mapping, true
+}
+
+func durationScore(titles []TitleInfo) float64 {
+	shortest, longest := titles[0].Duration, titles[len(titles)-1].Duration
+	// anything under a minute is a menu loop or logo, not an episode
+	if shortest < time.Minute {
+		return 0
+	}
+	return 1 - float64(longest-shortest)/float64(longest)
+}
+
+func chapterScore(titles []TitleInfo) float64 {
+	counts := make(map[int]int)
+	most := 0
+	for _, title := range titles {
+		counts[title.ChapterCount]++
+		if counts[title.ChapterCount] > most {
+			most = counts[title.ChapterCount]
+		}
+	}
+	return float64(most) / float64(len(titles))
+}
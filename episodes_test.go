@@ -0,0 +1,31 @@
+package makemkv
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchEpisodes(t *testing.T) {
+	disc := DiscInfo{
+		Titles: []TitleInfo{
+			{Id: 0, SourceFileName: "00800.mpls", Duration: 132 * time.Minute, ChapterCount: 24},
+			{Id: 1, SourceFileName: "00803.mpls", Duration: 44 * time.Minute, ChapterCount: 8},
+			{Id: 2, SourceFileName: "00801.mpls", Duration: 45 * time.Minute, ChapterCount: 8},
+			{Id: 3, SourceFileName: "00802.mpls", Duration: 43 * time.Minute, ChapterCount: 8},
+			{Id: 4, SourceFileName: "00900.m2ts", Duration: 2 * time.Minute, ChapterCount: 1},
+		},
+	}
+	mapping, ok := MatchEpisodes(disc, 3)
+	assert.True(t, ok)
+	assert.Equal(t, []EpisodeMatch{
+		{TitleId: 2, Episode: 1},
+		{TitleId: 3, Episode: 2},
+		{TitleId: 1, Episode: 3},
+	}, mapping.Episodes)
+	assert.InDelta(t, 1-2.0/45.0, mapping.Confidence, 0.0001)
+
+	_, ok = MatchEpisodes(disc, 6)
+	assert.False(t, ok)
+}
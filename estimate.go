@@ -0,0 +1,114 @@
+package makemkv
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrUnsupportedDrop is returned for a ConversionPlan dropping a stream that
+// isn't an audio stream, whose size makemkvcon doesn't report
+var ErrUnsupportedDrop = errors.New("makemkv: only audio streams can be dropped from an estimate")
+
+type AudioTarget string
+
+const (
+	AudioFlac AudioTarget = "FLAC"
+	AudioAac  AudioTarget = "AAC"
+	AudioAc3  AudioTarget = "AC3"
+)
+
+// ConversionPlan describes how a title will differ from what makemkvcon
+// reports as its size. Stream ids are makemkvcon stream ids. DropStreams may
+// only name audio streams.
+type ConversionPlan struct {
+	DropStreams      []int
+	AudioConversions map[int]AudioTarget
+}
+
+// EstimateOutputSize adjusts the title's reported size for dropped audio
+// streams and audio conversions. Streams without a known bit rate are left at
+// whatever share of the reported size they already had. Dropping anything
+// but an audio stream of the title is an ErrUnsupportedDrop.
+func EstimateOutputSize(title TitleInfo, plan ConversionPlan) (int64, error) {
+	seconds := title.Duration.Seconds()
+	audio := make(map[int]bool, len(title.AudioStreams))
+	for _, stream := range title.AudioStreams {
+		audio[stream.Id] = true
+	}
+	dropped := make(map[int]bool, len(plan.DropStreams))
+	for _, id := range plan.DropStreams {
+		if !audio[id] {
+			return 0, fmt.Errorf("%w: stream %d", ErrUnsupportedDrop, id)
+		}
+		dropped[id] = true
+	}
+
+	size := title.FileSize
+	for _, stream := range title.AudioStreams {
+		rate := parseBitRate(stream.BitRate)
+		if rate == 0 {
+			continue
+		}
+		original := int64(float64(rate) / 8 * seconds)
+		if dropped[stream.Id] {
+			size -= original
+			continue
+		}
+		if target, ok := plan.AudioConversions[stream.Id]; ok {
+			if converted, ok := convertedBitRate(stream, target); ok {
+				size += int64(float64(converted)/8*seconds) - original
+			}
+		}
+	}
+	if size < 0 {
+		return 0, nil
+	}
+	return size, nil
+}
+
+func convertedBitRate(stream AudioStreamInfo, target AudioTarget) (int64, bool) {
+	channels := int64(stream.ChannelCount)
+	if channels == 0 {
+		channels = 2
+	}
+	switch target {
+	case AudioFlac:
+		sampleRate, sampleSize := int64(stream.SampleRate), int64(stream.SampleSize)
+		if sampleRate == 0 || sampleSize == 0 {
+			return 0, false
+		}
+		// flac typically lands around 60% of the raw pcm rate for film audio
+		return sampleRate * sampleSize * channels * 6 / 10, true
+	case AudioAac:
+		return 64000 * channels, true
+	case AudioAc3:
+		if channels > 2 {
+			return 640000, true
+		}
+		return 192000, true
+	default:
+		return 0, false
+	}
+}
+
+// parseBitRate parses makemkvcon bit rates like "640 Kb/s" into bits per
+// second, returning 0 when the value can't be read
+func parseBitRate(value string) int64 {
+	number, unit, _ := strings.Cut(strings.TrimSpace(value), " ")
+	n, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0
+	}
+	switch strings.ToLower(unit) {
+	case "b/s":
+		return int64(n)
+	case "kb/s":
+		return int64(n * 1000)
+	case "mb/s":
+		return int64(n * 1000 * 1000)
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,49 @@
+package makemkv
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseBitRate(t *testing.T) {
+	assert.Equal(t, int64(640000), parseBitRate("640 Kb/s"))
+	assert.Equal(t, int64(4500000), parseBitRate("4.5 Mb/s"))
+	assert.Equal(t, int64(0), parseBitRate(""))
+}
+
+func TestEstimateOutputSize(t *testing.T) {
+	title := TitleInfo{
+		Duration: 100 * time.Second,
+		FileSize: 100_000_000,
+		AudioStreams: []AudioStreamInfo{
+			{Id: 1, BitRate: "4000 Kb/s", ChannelCount: 8, SampleRate: 48000, SampleSize: 24},
+			{Id: 2, BitRate: "640 Kb/s", ChannelCount: 6},
+			{Id: 3, ChannelCount: 2},
+		},
+	}
+	size, err := EstimateOutputSize(title, ConversionPlan{})
+	assert.Nil(t, err)
+	assert.Equal(t, title.FileSize, size)
+
+	// dropping the 640 Kb/s track saves 8MB over 100 seconds
+	size, err = EstimateOutputSize(title, ConversionPlan{DropStreams: []int{2}})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(92_000_000), size)
+
+	// 48000 * 24 * 8 * 0.6 = 5529600 b/s replacing 4000000 b/s
+	size, err = EstimateOutputSize(title, ConversionPlan{
+		AudioConversions: map[int]AudioTarget{1: AudioFlac, 3: AudioAac},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(100_000_000+19_120_000), size)
+
+	// video and subtitle streams have no bit rate to take off
+	title.VideoStreams = []VideoStreamInfo{{Id: 0}}
+	title.SubtitleStreams = []SubtitleStreamInfo{{Id: 4}}
+	for _, id := range []int{0, 4, 9} {
+		_, err = EstimateOutputSize(title, ConversionPlan{DropStreams: []int{2, id}})
+		assert.ErrorIs(t, err, ErrUnsupportedDrop, id)
+	}
+}
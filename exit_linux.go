@@ -0,0 +1,25 @@
+package makemkv
+
+import (
+	"os/exec"
+	"runtime"
+	"syscall"
+	"unsafe"
+)
+
+// waitExited blocks until cmd's process has exited without reaping it, so
+// its pid and process group id can't be reused until cmd.Wait. It reports
+// false when the kernel can't do that.
+func waitExited(cmd *exec.Cmd) bool {
+	const pPid = 1
+	// siginfo_t, which is 128 bytes on every architecture
+	var info [128]byte
+	for {
+		_, _, errno := syscall.Syscall6(syscall.SYS_WAITID, pPid, uintptr(cmd.Process.Pid),
+			uintptr(unsafe.Pointer(&info[0])), syscall.WEXITED|syscall.WNOWAIT, 0, 0)
+		runtime.KeepAlive(cmd.Process)
+		if errno != syscall.EINTR {
+			return errno == 0
+		}
+	}
+}
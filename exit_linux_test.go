@@ -0,0 +1,41 @@
+package makemkv
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunCommandKillsLeftovers(t *testing.T) {
+	// the helper keeps running in makemkvcon's process group after it exits
+	cmd := exec.Command("sh", "-c", "sleep 60 >/dev/null 2>&1 & echo $!")
+	setProcessGroup(cmd)
+	var helper int
+	var s stopper
+	_, err := runCommand(cmd, &s, "", nil, func(out io.Reader) error {
+		data, err := io.ReadAll(out)
+		helper, _ = strconv.Atoi(strings.TrimSpace(string(data)))
+		return err
+	})
+	assert.Nil(t, err)
+	if assert.NotZero(t, helper) {
+		assert.Eventually(t, func() bool { return gone(helper) }, time.Second, 10*time.Millisecond)
+	}
+}
+
+// gone reports whether pid has died, whatever adopted it may not have
+// reaped it yet
+func gone(pid int) bool {
+	stat, err := os.ReadFile("/proc/" + strconv.Itoa(pid) + "/stat")
+	if err != nil {
+		return true
+	}
+	_, state, _ := strings.Cut(string(stat), ") ")
+	return strings.HasPrefix(state, "Z")
+}
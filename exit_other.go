@@ -0,0 +1,11 @@
+//go:build !linux
+
+package makemkv
+
+import "os/exec"
+
+// waitExited can't wait for an exit without reaping the process here, so
+// leftover helpers are only signalled when a job is stopped
+func waitExited(cmd *exec.Cmd) bool {
+	return false
+}
@@ -0,0 +1,139 @@
+package makemkv
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Row is one title being exported, along with where it came from
+type Row struct {
+	// catalog path of the disc, empty when exporting a single disc
+	Path  string
+	Disc  *DiscInfo
+	Title *TitleInfo
+}
+
+type Column struct {
+	Name  string
+	Value func(Row) string
+}
+
+var (
+	ColumnPath     = Column{"path", func(r Row) string { return r.Path }}
+	ColumnDisc     = Column{"disc", func(r Row) string { return r.Disc.Name }}
+	ColumnTitle    = Column{"title", func(r Row) string { return strconv.Itoa(r.Title.Id) }}
+	ColumnName     = Column{"name", func(r Row) string { return r.Title.Name }}
+	ColumnDuration = Column{"duration", func(r Row) string { return r.Title.Duration.String() }}
+	ColumnChapters = Column{"chapters", func(r Row) string { return strconv.Itoa(r.Title.ChapterCount) }}
+	ColumnSize     = Column{"size", func(r Row) string { return strconv.FormatInt(r.Title.FileSize, 10) }}
+	ColumnVideo    = Column{"video", func(r Row) string {
+		return joinStreams(r.Title.VideoStreams, func(s VideoStreamInfo) string { return s.CodecShort })
+	}}
+	ColumnAudio = Column{"audio", func(r Row) string {
+		return joinStreams(r.Title.AudioStreams, func(s AudioStreamInfo) string { return s.CodecShort })
+	}}
+	ColumnAudioLanguages = Column{"audio_languages", func(r Row) string {
+		return joinStreams(r.Title.AudioStreams, func(s AudioStreamInfo) string { return s.LangCode })
+	}}
+	ColumnSubtitleLanguages = Column{"subtitle_languages", func(r Row) string {
+		return joinStreams(r.Title.SubtitleStreams, func(s SubtitleStreamInfo) string { return s.LangCode })
+	}}
+)
+
+var DefaultColumns = []Column{
+	ColumnPath, ColumnTitle, ColumnName, ColumnDuration, ColumnSize,
+	ColumnVideo, ColumnAudio, ColumnAudioLanguages, ColumnSubtitleLanguages,
+}
+
+func joinStreams[S any](streams []S, value func(S) string) string {
+	values := make([]string, len(streams))
+	for i, s := range streams {
+		values[i] = value(s)
+	}
+	return strings.Join(values, ";")
+}
+
+func discRows(path string, disc *DiscInfo) []Row {
+	rows := make([]Row, len(disc.Titles))
+	for i := range disc.Titles {
+		rows[i] = Row{Path: path, Disc: disc, Title: &disc.Titles[i]}
+	}
+	return rows
+}
+
+// catalogRows skips entries that failed to scan
+func catalogRows(catalog Catalog) []Row {
+	var rows []Row
+	for _, path := range catalog.Paths() {
+		if disc := catalog[path].Disc; disc != nil {
+			rows = append(rows, discRows(path, disc)...)
+		}
+	}
+	return rows
+}
+
+// WriteCSV writes one line per title with a header naming the columns,
+// DefaultColumns when none are given
+func WriteCSV(w io.Writer, disc DiscInfo, columns ...Column) error {
+	return writeCSV(w, discRows("", &disc), columns)
+}
+
+func WriteCatalogCSV(w io.Writer, catalog Catalog, columns ...Column) error {
+	return writeCSV(w, catalogRows(catalog), columns)
+}
+
+// WriteJSONL writes one JSON object per title with the columns as fields
+func WriteJSONL(w io.Writer, disc DiscInfo, columns ...Column) error {
+	return writeJSONL(w, discRows("", &disc), columns)
+}
+
+func WriteCatalogJSONL(w io.Writer, catalog Catalog, columns ...Column) error {
+	return writeJSONL(w, catalogRows(catalog), columns)
+}
+
+func writeCSV(w io.Writer, rows []Row, columns []Column) error {
+	if len(columns) == 0 {
+		columns = DefaultColumns
+	}
+	cw := csv.NewWriter(w)
+	record := make([]string, len(columns))
+	for i, column := range columns {
+		record[i] = column.Name
+	}
+	cw.Write(record)
+	for _, row := range rows {
+		for i, column := range columns {
+			record[i] = column.Value(row)
+		}
+		cw.Write(record)
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func writeJSONL(w io.Writer, rows []Row, columns []Column) error {
+	if len(columns) == 0 {
+		columns = DefaultColumns
+	}
+	bw := bufio.NewWriter(w)
+	// written by hand to keep fields in column order
+	for _, row := range rows {
+		bw.WriteByte('{')
+		for i, column := range columns {
+			if i > 0 {
+				bw.WriteByte(',')
+			}
+			name, _ := json.Marshal(column.Name)
+			value, _ := json.Marshal(column.Value(row))
+			bw.Write(name)
+			bw.WriteByte(':')
+			bw.Write(value)
+		}
+		bw.WriteString("}\n")
+	}
+	return bw.Flush()
+}
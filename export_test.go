@@ -0,0 +1,41 @@
+package makemkv
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func exportDisc() DiscInfo {
+	return DiscInfo{Name: "Movie", Titles: []TitleInfo{{
+		Id:              0,
+		Name:            "Feature, Extended",
+		Duration:        90 * time.Minute,
+		FileSize:        1024,
+		VideoStreams:    []VideoStreamInfo{{CodecShort: "MPEG4-AVC"}},
+		AudioStreams:    []AudioStreamInfo{{CodecShort: "DTS-HD MA", LangCode: "eng"}, {CodecShort: "AC3", LangCode: "fra"}},
+		SubtitleStreams: []SubtitleStreamInfo{{LangCode: "eng"}},
+	}}}
+}
+
+func TestWriteCSV(t *testing.T) {
+	var sb strings.Builder
+	assert.Nil(t, WriteCSV(&sb, exportDisc()))
+	assert.Equal(t, "path,title,name,duration,size,video,audio,audio_languages,subtitle_languages\n"+
+		",0,\"Feature, Extended\",1h30m0s,1024,MPEG4-AVC,DTS-HD MA;AC3,eng;fra,eng\n", sb.String())
+}
+
+func TestWriteCatalogJSONL(t *testing.T) {
+	disc := exportDisc()
+	catalog := Catalog{
+		"/lib/b.iso": {Disc: &disc},
+		"/lib/a.iso": {Disc: &disc},
+		"/lib/c.iso": {Err: assert.AnError},
+	}
+	var sb strings.Builder
+	assert.Nil(t, WriteCatalogJSONL(&sb, catalog, ColumnPath, ColumnDisc, ColumnTitle))
+	assert.Equal(t, `{"path":"/lib/a.iso","disc":"Movie","title":"0"}`+"\n"+
+		`{"path":"/lib/b.iso","disc":"Movie","title":"0"}`+"\n", sb.String())
+}
@@ -0,0 +1,90 @@
+package makemkv
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	ErrKeyExpired  = errors.New("makemkv: registration key or evaluation period expired")
+	ErrDiscOpen    = errors.New("makemkv: failed to open disc")
+	ErrHashCheck   = errors.New("makemkv: hash check failed")
+	ErrDiscRead    = errors.New("makemkv: disc read errors")
+	ErrTitleFailed = errors.New("makemkv: failed to save title")
+)
+
+// FailureError is a failed job along with the cause makemkvcon gave for it.
+// It matches its Cause with errors.Is and unwraps to the exit error.
+type FailureError struct {
+	Cause error
+	// the message the cause was recognized from
+	Message Message
+	Err     error
+}
+
+func (e *FailureError) Error() string {
+	return e.Cause.Error() + ": " + e.Message.Text
+}
+
+func (e *FailureError) Is(target error) bool {
+	return target == e.Cause
+}
+
+func (e *FailureError) Unwrap() error {
+	return e.Err
+}
+
+// failure causes in the order they are blamed when several are seen, a disc
+// that couldn't be opened also fails to save its titles
+var failureCauses = [...]struct {
+	cause error
+	match func(format string) bool
+}{
+	{ErrKeyExpired, func(f string) bool {
+		return strings.Contains(f, "evaluation period") || strings.Contains(f, "too old") || strings.Contains(f, "key has expired")
+	}},
+	{ErrDiscOpen, func(f string) bool { return strings.HasPrefix(f, "Failed to open disc") }},
+	{ErrHashCheck, func(f string) bool { return strings.HasPrefix(f, "Hash check failed") }},
+	{ErrDiscRead, func(f string) bool {
+		return strings.Contains(f, "occurred while reading") || strings.Contains(f, "Too many read errors")
+	}},
+	{ErrTitleFailed, func(f string) bool { return strings.HasPrefix(f, "Failed to save title") }},
+}
+
+// failureWatch remembers the first message seen for each failure cause
+type failureWatch struct {
+	seen [len(failureCauses)]*Message
+}
+
+func (w *failureWatch) observe(msg Message) {
+	for i, c := range failureCauses {
+		if w.seen[i] == nil && c.match(msg.Format) {
+			w.seen[i] = &msg
+		}
+	}
+}
+
+// wrap gives err the most serious cause that was seen, leaving stopped jobs,
+// partial successes and successes alone
+func (w *failureWatch) wrap(err error) error {
+	var stopped *StoppedError
+	if err == nil || errors.As(err, &stopped) || errors.Is(err, ErrPartialSuccess) {
+		return err
+	}
+	for i, c := range failureCauses {
+		if msg := w.seen[i]; msg != nil {
+			return &FailureError{Cause: c.cause, Message: *msg, Err: err}
+		}
+	}
+	return err
+}
+
+// message returns the first message seen for cause, nil when there was none
+func (w *failureWatch) message(cause error) *Message {
+	for i, c := range failureCauses {
+		if c.cause == cause {
+			return w.seen[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package makemkv
+
+import (
+	"errors"
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFailureWatch(t *testing.T) {
+	exitErr := &exec.ExitError{}
+
+	var watch failureWatch
+	assert.Nil(t, watch.wrap(nil))
+	assert.Equal(t, exitErr, watch.wrap(exitErr), "nothing recognized")
+
+	watch.observe(Message{Code: 5003, Text: "Failed to save title 0 to file /out/t00.mkv", Format: "Failed to save title %1 to file %2"})
+	watch.observe(Message{Code: 5010, Text: "Failed to open disc", Format: "Failed to open disc"})
+	err := watch.wrap(exitErr)
+	assert.ErrorIs(t, err, ErrDiscOpen)
+	assert.NotErrorIs(t, err, ErrTitleFailed)
+	var exit *exec.ExitError
+	assert.True(t, errors.As(err, &exit))
+	assert.Equal(t, "makemkv: failed to open disc: Failed to open disc", err.Error())
+
+	watch.observe(Message{Code: 5021, Text: "This application version is too old", Format: "This application version is too old. Please download the latest version"})
+	assert.ErrorIs(t, watch.wrap(exitErr), ErrKeyExpired)
+
+	stopped := &StoppedError{Reason: StopCanceled, Err: exitErr}
+	assert.Equal(t, stopped, watch.wrap(stopped))
+	partial := &PartialSuccessError{Saved: 1, Failed: 1}
+	assert.Equal(t, partial, watch.wrap(partial))
+	assert.ErrorIs(t, partial, ErrTitleFailed)
+}
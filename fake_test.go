@@ -0,0 +1,247 @@
+package makemkv
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/aravance/go-makemkv/makemkvtest"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestMain lets the test binary stand in for makemkvcon
+func TestMain(m *testing.M) {
+	makemkvtest.Main()
+	os.Exit(m.Run())
+}
+
+// fakeMakemkvcon returns options that run the test binary in place of
+// makemkvcon, printing output and exiting with code
+func fakeMakemkvcon(t *testing.T, output string, code int) MkvOptions {
+	fake := makemkvtest.Output(t, output, code)
+	return MkvOptions{Binary: fake.Binary, Env: fake.Env}
+}
+
+// fakeMakemkvconScript is fakeMakemkvcon with the output depending on the
+// command line, see makemkvtest.Script
+func fakeMakemkvconScript(t *testing.T, outputs map[string]string, code int) MkvOptions {
+	fake := makemkvtest.Script(t, outputs, code)
+	return MkvOptions{Binary: fake.Binary, Env: fake.Env}
+}
+
+func TestFakeInfo(t *testing.T) {
+	for _, transport := range []Transport{TransportStdout, TransportFile} {
+		opts := fakeMakemkvcon(t, input, 0)
+		opts.Transport = transport
+		disc, err := Info(NewIsoDevice("/disc.iso"), opts).Run()
+		if assert.Nil(t, err) {
+			assert.Equal(t, "DiscName", disc.Name)
+			assert.Equal(t, 3, len(disc.Titles))
+		}
+	}
+}
+
+func TestFakeMkv(t *testing.T) {
+	opts := fakeMakemkvcon(t, `MSG:1005,0,1,"MakeMKV v1.17.6 linux(x64-release) started","%1 started","MakeMKV v1.17.6 linux(x64-release)"
+PRGT:5018,0,"Saving to MKV file"
+PRGV:0,0,65536
+PRGV:65536,65536,65536
+MSG:5036,0,2,"Copy complete. 1 titles saved.","Copy complete. %1 titles saved.","1"
+`, 0)
+	job := Mkv(NewIsoDevice("/disc.iso"), 0, t.TempDir(), opts)
+	job.Statuschan = make(chan Status, 10)
+	result, err := job.RunContext(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, OutcomeSuccess, result.Outcome)
+	assert.Equal(t, 1, result.Saved)
+	assert.Equal(t, 3, len(job.Statuschan), "two statuses and the final one")
+
+	opts = fakeMakemkvcon(t, "", 1)
+	err = Mkv(NewIsoDevice("/disc.iso"), 0, t.TempDir(), opts).Run()
+	assert.NotNil(t, err)
+}
+
+func TestFakeInfoStatus(t *testing.T) {
+	opts := fakeMakemkvcon(t, `PRGT:5018,0,"Scanning CD-ROM devices"
+PRGC:5018,0,"Opening disc"
+PRGV:0,0,65536
+PRGV:65536,65536,65536
+`+input, 0)
+	job := Info(NewIsoDevice("/disc.iso"), opts)
+	job.Statuschan = make(chan Status, 10)
+	_, err := job.Run()
+	assert.Nil(t, err)
+	close(job.Statuschan)
+	var statuses []Status
+	for status := range job.Statuschan {
+		statuses = append(statuses, status)
+	}
+	assert.Equal(t, []Status{
+		{Title: "Scanning CD-ROM devices", Channel: "Opening disc", TitleCode: 5018, ChannelCode: 5018, Current: 0, Total: 0, Max: 65536, Seq: 1},
+		{Title: "Scanning CD-ROM devices", Channel: "Opening disc", TitleCode: 5018, ChannelCode: 5018, Current: 65536, Total: 65536, Max: 65536, Seq: 2},
+		{Title: "Scanning CD-ROM devices", Channel: "Opening disc", TitleCode: 5018, ChannelCode: 5018, Current: 65536, Total: 65536, Max: 65536, Seq: 3, Final: true},
+	}, statuses)
+}
+
+func TestFakeMkvRetry(t *testing.T) {
+	const nothing = `MSG:5036,0,2,"Copy complete. 0 titles saved.","Copy complete. %1 titles saved.","0"
+`
+	const saved = `MSG:5036,0,2,"Copy complete. 1 titles saved.","Copy complete. %1 titles saved.","1"
+`
+	const rescan = `TCOUNT:2
+TINFO:0,9,0,"0:22:00"
+TINFO:1,9,0,"1:45:00"
+`
+	expect := &TitleInfo{Id: 0, Duration: time.Hour + 45*time.Minute}
+	opts := fakeMakemkvconScript(t, map[string]string{
+		"mkv iso:/disc.iso 0": nothing,
+		"info iso:/disc.iso":  rescan,
+		"mkv iso:/disc.iso 1": saved,
+	}, 0)
+	job := Mkv(NewIsoDevice("/disc.iso"), 0, t.TempDir(), opts)
+	job.Expect = expect
+	result, err := job.RunContext(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, 1, result.Saved)
+	assert.Equal(t, "0", job.titleId)
+	assert.Nil(t, job.Scanned)
+
+	// only a missing title is worth another scan
+	opts = fakeMakemkvconScript(t, map[string]string{
+		"mkv iso:/disc.iso 0": `MSG:5021,260,1,"This application version is too old.","This application version is too old.",""
+` + nothing,
+		"info iso:/disc.iso":  rescan,
+		"mkv iso:/disc.iso 1": saved,
+	}, 1)
+	job = Mkv(NewIsoDevice("/disc.iso"), 0, t.TempDir(), opts)
+	job.Expect = expect
+	result, err = job.RunContext(context.Background())
+	assert.ErrorIs(t, err, ErrKeyExpired)
+	assert.Equal(t, 0, result.Saved)
+}
+
+func TestFakeMkvOrder(t *testing.T) {
+	opts := fakeMakemkvcon(t, `MSG:1005,0,1,"MakeMKV v1.17.6 linux(x64-release) started","%1 started","MakeMKV v1.17.6 linux(x64-release)"
+PRGT:5018,0,"Saving to MKV file"
+PRGV:0,0,65536
+MSG:3307,0,2,"File 00001.mpls was added as title #0","File %1 was added as title #%2","00001.mpls","0"
+PRGV:32768,32768,65536
+PRGV:65536,65536,65536
+MSG:5036,0,2,"Copy complete. 1 titles saved.","Copy complete. %1 titles saved.","1"
+`, 0)
+	job := Mkv(NewIsoDevice("/disc.iso"), 0, t.TempDir(), opts)
+	job.Statuschan = make(chan Status, 10)
+	job.Messagechan = make(chan Message, 10)
+	_, err := job.RunContext(context.Background())
+	assert.Nil(t, err)
+	close(job.Statuschan)
+	close(job.Messagechan)
+
+	events := make(map[uint64]string)
+	var last Status
+	for status := range job.Statuschan {
+		events[status.Seq] = "PRGV:" + strconv.Itoa(status.Current)
+		last = status
+	}
+	for msg := range job.Messagechan {
+		events[msg.Seq] = "MSG:" + strconv.Itoa(msg.Code)
+	}
+	var order []string
+	for seq := uint64(1); seq <= uint64(len(events)); seq++ {
+		order = append(order, events[seq])
+	}
+	assert.Equal(t, []string{"MSG:1005", "PRGV:0", "MSG:3307", "PRGV:32768", "PRGV:65536", "MSG:5036", "PRGV:65536"}, order)
+	assert.True(t, last.Final)
+	assert.Equal(t, uint64(7), last.Seq)
+}
+
+func TestFakeMkvPartialFile(t *testing.T) {
+	dest := t.TempDir()
+	for _, name := range []string{"title_t00.mkv", "title_t01.mkv"} {
+		assert.Nil(t, os.WriteFile(filepath.Join(dest, name), []byte("mkv"), 0o644))
+	}
+	opts := fakeMakemkvcon(t, `MSG:5003,0,2,"Failed to save title 1 to file `+dest+`/title_t01.mkv","Failed to save title %1 to file %2","1","`+dest+`/title_t01.mkv"
+MSG:5037,0,2,"Copy complete. 1 titles saved, 1 failed.","Copy complete. %1 titles saved, %2 failed.","1","1"
+`, 0)
+	result, err := Mkv(NewIsoDevice("/disc.iso"), 0, dest, opts).RunContext(context.Background())
+	assert.NotNil(t, err)
+	assert.Equal(t, OutcomePartial, result.Outcome)
+	assert.Equal(t, 2, len(result.Files))
+	assert.False(t, result.SummaryMismatch, "the failed title's partial file isn't a saved title")
+}
+
+func TestFakeMkvTitles(t *testing.T) {
+	opts := fakeMakemkvconScript(t, map[string]string{
+		"mkv iso:/disc.iso 1": `PRGT:5018,0,"Saving to MKV file"
+PRGV:65536,65536,65536
+MSG:5036,0,2,"Copy complete. 1 titles saved.","Copy complete. %1 titles saved.","1"
+`,
+		"mkv iso:/disc.iso 3": `PRGT:5018,0,"Saving to MKV file"
+PRGV:100,100,65536
+MSG:5003,0,2,"Failed to save title 3 to file /out/title_t03.mkv","Failed to save title %1 to file %2","3","/out/title_t03.mkv"
+MSG:5037,0,2,"Copy complete. 0 titles saved, 1 failed.","Copy complete. %1 titles saved, %2 failed.","0","1"
+`,
+	}, 0)
+	job := MkvTitles(NewIsoDevice("/disc.iso"), []int{1, 3}, t.TempDir(), opts)
+	job.Statuschan = make(chan Status, 10)
+	result, err := job.RunContext(context.Background())
+	var partial *PartialSuccessError
+	if assert.ErrorAs(t, err, &partial) {
+		assert.Equal(t, []int{3}, partial.Titles)
+	}
+	assert.Equal(t, OutcomePartial, result.Outcome)
+	assert.Equal(t, 1, result.Saved)
+	assert.Equal(t, 1, result.Failed)
+	assert.Equal(t, []TitleResult{{TitleId: 3, File: "/out/title_t03.mkv"}}, result.Titles)
+	assert.Equal(t, 7, result.Report.Lines)
+
+	close(job.Statuschan)
+	var saving []string
+	var finals int
+	var last uint64
+	for status := range job.Statuschan {
+		saving = append(saving, status.SavingTitle)
+		assert.Greater(t, status.Seq, last)
+		last = status.Seq
+		if status.Final {
+			finals++
+		}
+	}
+	assert.Equal(t, []string{"1", "3", "3"}, saving)
+	assert.Equal(t, 1, finals, "one final status for the whole job")
+}
+
+func TestFakeMkvRepeatedMessages(t *testing.T) {
+	retry := func(offset string) string {
+		return `MSG:2003,0,1,"Error reading at offset ` + offset + `","Error reading at offset %1","` + offset + `"
+`
+	}
+	opts := fakeMakemkvcon(t, retry("1")+retry("2")+retry("3")+`MSG:5036,0,2,"Copy complete. 1 titles saved.","Copy complete. %1 titles saved.","1"
+`, 0)
+	var log bytes.Buffer
+	job := Mkv(NewIsoDevice("/disc.iso"), 0, t.TempDir(), opts)
+	job.Messagechan = make(chan Message, 10)
+	job.Logger = slog.New(slog.NewTextHandler(&log, nil))
+	result, err := job.RunContext(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, []MessageCount{{Code: 2003, Text: "Error reading at offset 1", Count: 3}}, result.RepeatedMessages)
+	close(job.Messagechan)
+
+	var messages []Message
+	for msg := range job.Messagechan {
+		messages = append(messages, msg)
+	}
+	if assert.Equal(t, 3, len(messages), "the first retry, the copy summary and one update") {
+		assert.Equal(t, 0, messages[0].Count)
+		assert.Equal(t, 2003, messages[2].Code)
+		assert.Equal(t, 3, messages[2].Count)
+		assert.Equal(t, "Error reading at offset 3", messages[2].Text)
+	}
+	assert.Contains(t, log.String(), "makemkv repeated message")
+	assert.Contains(t, log.String(), "count=3")
+}
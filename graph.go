@@ -0,0 +1,122 @@
+package makemkv
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// WriteDot renders the disc as a Graphviz digraph of disc -> title ->
+// segment/stream. Segments shared between titles become shared nodes, which
+// is what makes playlist obfuscation visible.
+func WriteDot(w io.Writer, disc DiscInfo) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "digraph disc {")
+	fmt.Fprintln(bw, "\trankdir=LR;")
+	fmt.Fprintf(bw, "\tdisc [shape=box, label=%q];\n", discLabel(disc))
+	for _, title := range disc.Titles {
+		fmt.Fprintf(bw, "\tt%d [shape=box, label=%q];\n", title.Id, titleLabel(title))
+		fmt.Fprintf(bw, "\tdisc -> t%d;\n", title.Id)
+		for _, stream := range titleStreams(title) {
+			fmt.Fprintf(bw, "\tt%ds%d [shape=ellipse, label=%q];\n", title.Id, stream.id, stream.label)
+			fmt.Fprintf(bw, "\tt%d -> t%ds%d;\n", title.Id, title.Id, stream.id)
+		}
+	}
+	for _, segment := range discSegments(disc) {
+		fmt.Fprintf(bw, "\tseg%d [shape=diamond, label=\"segment %d\"];\n", segment, segment)
+	}
+	for _, title := range disc.Titles {
+		for _, segment := range title.Segments {
+			fmt.Fprintf(bw, "\tt%d -> seg%d [style=dashed];\n", title.Id, segment)
+		}
+	}
+	fmt.Fprintln(bw, "}")
+	return bw.Flush()
+}
+
+// WriteMermaid renders the same graph as WriteDot as a Mermaid flowchart.
+func WriteMermaid(w io.Writer, disc DiscInfo) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "flowchart LR")
+	fmt.Fprintf(bw, "\tdisc[%s]\n", mermaidText(discLabel(disc)))
+	for _, title := range disc.Titles {
+		fmt.Fprintf(bw, "\tt%d[%s]\n", title.Id, mermaidText(titleLabel(title)))
+		fmt.Fprintf(bw, "\tdisc --> t%d\n", title.Id)
+		for _, stream := range titleStreams(title) {
+			fmt.Fprintf(bw, "\tt%ds%d(%s)\n", title.Id, stream.id, mermaidText(stream.label))
+			fmt.Fprintf(bw, "\tt%d --> t%ds%d\n", title.Id, title.Id, stream.id)
+		}
+	}
+	for _, segment := range discSegments(disc) {
+		fmt.Fprintf(bw, "\tseg%d{{\"segment %d\"}}\n", segment, segment)
+	}
+	for _, title := range disc.Titles {
+		for _, segment := range title.Segments {
+			fmt.Fprintf(bw, "\tt%d -.-> seg%d\n", title.Id, segment)
+		}
+	}
+	return bw.Flush()
+}
+
+type graphStream struct {
+	id    int
+	label string
+}
+
+func discLabel(disc DiscInfo) string {
+	if disc.Name != "" {
+		return disc.Name
+	}
+	if disc.VolumeName != "" {
+		return disc.VolumeName
+	}
+	return "disc"
+}
+
+func titleLabel(title TitleInfo) string {
+	label := fmt.Sprintf("title %d", title.Id)
+	if title.SourceFileName != "" {
+		label += " " + title.SourceFileName
+	}
+	return label + fmt.Sprintf(" (%s, %d chapters)", title.Duration, title.ChapterCount)
+}
+
+func titleStreams(title TitleInfo) []graphStream {
+	var streams []graphStream
+	for _, s := range title.VideoStreams {
+		streams = append(streams, graphStream{s.Id, strings.TrimSpace("video " + s.CodecShort + " " + s.VideoSize)})
+	}
+	for _, s := range title.AudioStreams {
+		streams = append(streams, graphStream{s.Id, strings.TrimSpace("audio " + s.LangCode + " " + s.CodecShort)})
+	}
+	for _, s := range title.SubtitleStreams {
+		streams = append(streams, graphStream{s.Id, strings.TrimSpace("subtitle " + s.LangCode + " " + s.CodecShort)})
+	}
+	sort.Slice(streams, func(a, b int) bool {
+		return streams[a].id < streams[b].id
+	})
+	return streams
+}
+
+func discSegments(disc DiscInfo) []int {
+	seen := make(map[int]bool)
+	var segments []int
+	for _, title := range disc.Titles {
+		for _, segment := range title.Segments {
+			if !seen[segment] {
+				seen[segment] = true
+				segments = append(segments, segment)
+			}
+		}
+	}
+	sort.Ints(segments)
+	return segments
+}
+
+// mermaidText quotes a label, mermaid has no escape for double quotes so
+// they're swapped for its #quot; entity
+func mermaidText(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, "#quot;") + `"`
+}
@@ -0,0 +1,52 @@
+package makemkv
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var graphDisc = DiscInfo{
+	Name: "Movie",
+	Titles: []TitleInfo{
+		{
+			Id:             0,
+			SourceFileName: "00800.mpls",
+			Duration:       90 * time.Minute,
+			ChapterCount:   12,
+			Segments:       []int{1, 2},
+			VideoStreams:   []VideoStreamInfo{{Id: 0, CodecShort: "MpegH", VideoSize: "3840x2160"}},
+			AudioStreams:   []AudioStreamInfo{{Id: 1, LangCode: "eng", CodecShort: "TrueHD"}},
+		},
+		{
+			Id:       1,
+			Duration: 90 * time.Minute,
+			Segments: []int{2, 3},
+		},
+	},
+}
+
+func TestWriteDot(t *testing.T) {
+	var sb strings.Builder
+	assert.Nil(t, WriteDot(&sb, graphDisc))
+	out := sb.String()
+	assert.True(t, strings.HasPrefix(out, "digraph disc {\n"))
+	assert.Contains(t, out, "\tt0 [shape=box, label=\"title 0 00800.mpls (1h30m0s, 12 chapters)\"];\n")
+	assert.Contains(t, out, "\tt0s1 [shape=ellipse, label=\"audio eng TrueHD\"];\n")
+	// segment 2 is shared, so it's declared once and linked from both titles
+	assert.Equal(t, 1, strings.Count(out, "seg2 [shape=diamond"))
+	assert.Contains(t, out, "\tt0 -> seg2 [style=dashed];\n")
+	assert.Contains(t, out, "\tt1 -> seg2 [style=dashed];\n")
+}
+
+func TestWriteMermaid(t *testing.T) {
+	var sb strings.Builder
+	assert.Nil(t, WriteMermaid(&sb, graphDisc))
+	out := sb.String()
+	assert.True(t, strings.HasPrefix(out, "flowchart LR\n"))
+	assert.Contains(t, out, "\tdisc[\"Movie\"]\n")
+	assert.Contains(t, out, "\tt0s0(\"video MpegH 3840x2160\")\n")
+	assert.Contains(t, out, "\tt1 -.-> seg3\n")
+}
@@ -0,0 +1,107 @@
+package makemkv
+
+import (
+	"sync"
+	"time"
+)
+
+const defaultHeartbeatInterval = 30 * time.Second
+
+// Heartbeat is sent while makemkvcon has been quiet for a while, so that a
+// supervisor can tell a slow scan from a hung one
+type Heartbeat struct {
+	// since the scan started
+	Elapsed time.Duration
+	// since makemkvcon last printed anything
+	Silent time.Duration
+	// the last MSG line, nil before the first
+	LastMessage *Message
+}
+
+// heartbeatWatch sends a Heartbeat every interval that makemkvcon stays
+// silent, from its own goroutine until close
+type heartbeatWatch struct {
+	ch       chan Heartbeat
+	interval time.Duration
+	start    time.Time
+	done     chan struct{}
+	exited   chan struct{}
+
+	mu      sync.Mutex
+	last    time.Time
+	message *Message
+}
+
+// newHeartbeatWatch returns nil when there is no channel, which is safe to use
+func newHeartbeatWatch(ch chan Heartbeat, interval time.Duration) *heartbeatWatch {
+	if ch == nil {
+		return nil
+	}
+	if interval <= 0 {
+		interval = defaultHeartbeatInterval
+	}
+	now := time.Now()
+	w := &heartbeatWatch{
+		ch:       ch,
+		interval: interval,
+		start:    now,
+		last:     now,
+		done:     make(chan struct{}),
+		exited:   make(chan struct{}),
+	}
+	go w.run()
+	return w
+}
+
+func (w *heartbeatWatch) run() {
+	defer close(w.exited)
+	timer := time.NewTimer(w.interval)
+	defer timer.Stop()
+	for {
+		select {
+		case <-w.done:
+			return
+		case now := <-timer.C:
+			w.mu.Lock()
+			silent, message := now.Sub(w.last), w.message
+			w.mu.Unlock()
+			if silent < w.interval {
+				timer.Reset(w.interval - silent)
+				continue
+			}
+			// a heartbeat nobody is ready for is stale by the next one
+			select {
+			case w.ch <- Heartbeat{Elapsed: now.Sub(w.start), Silent: silent, LastMessage: message}:
+			default:
+			}
+			timer.Reset(w.interval)
+		}
+	}
+}
+
+func (w *heartbeatWatch) observe(prefix []byte, content []byte) {
+	if w == nil {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.last = time.Now()
+}
+
+func (w *heartbeatWatch) observeMessage(msg Message) {
+	if w == nil {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.message = &msg
+}
+
+// close stops the heartbeats, none are sent once it returns
+func (w *heartbeatWatch) close() {
+	if w == nil {
+		return
+	}
+	close(w.done)
+	<-w.exited
+}
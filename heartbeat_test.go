@@ -0,0 +1,43 @@
+package makemkv
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aravance/go-makemkv/makemkvtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFakeInfoHeartbeat(t *testing.T) {
+	opts := fakeMakemkvcon(t, `MSG:1005,0,1,"MakeMKV v1.17.6 linux(x64-release) started","%1 started","MakeMKV v1.17.6 linux(x64-release)"
+`, 0)
+	opts.Env = append(opts.Env, makemkvtest.Hold(300*time.Millisecond))
+	job := Info(NewIsoDevice("/disc.iso"), opts)
+	job.Heartbeatchan = make(chan Heartbeat, 10)
+	job.HeartbeatInterval = 50 * time.Millisecond
+	_, err := job.RunContext(context.Background())
+	assert.Nil(t, err)
+	close(job.Heartbeatchan)
+
+	var beats []Heartbeat
+	for beat := range job.Heartbeatchan {
+		beats = append(beats, beat)
+	}
+	if assert.NotEmpty(t, beats) {
+		beat := beats[len(beats)-1]
+		assert.GreaterOrEqual(t, beat.Silent, 50*time.Millisecond)
+		assert.GreaterOrEqual(t, beat.Elapsed, beat.Silent)
+		if assert.NotNil(t, beat.LastMessage) {
+			assert.Equal(t, 1005, beat.LastMessage.Code)
+		}
+	}
+
+	// a chatty scan has no silence to report
+	job = Info(NewIsoDevice("/disc.iso"), fakeMakemkvcon(t, input, 0))
+	job.Heartbeatchan = make(chan Heartbeat, 10)
+	job.HeartbeatInterval = time.Minute
+	_, err = job.RunContext(context.Background())
+	assert.Nil(t, err)
+	assert.Empty(t, job.Heartbeatchan)
+}
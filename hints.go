@@ -0,0 +1,96 @@
+package makemkv
+
+import "strings"
+
+type HintKind string
+
+const (
+	HintEdition HintKind = "edition"
+	HintRegion  HintKind = "region"
+)
+
+type DiscHint struct {
+	Kind   HintKind
+	Value  string
+	Source string
+}
+
+var editionTokens = map[string]string{
+	"EXTENDED":    "Extended",
+	"DIRECTORS":   "Director's Cut",
+	"DC":          "Director's Cut",
+	"THEATRICAL":  "Theatrical",
+	"UNRATED":     "Unrated",
+	"UNCUT":       "Uncut",
+	"REMASTERED":  "Remastered",
+	"ANNIVERSARY": "Anniversary",
+	"COLLECTORS":  "Collector's Edition",
+	"SPECIAL":     "Special Edition",
+	"SE":          "Special Edition",
+	"CE":          "Collector's Edition",
+	"IMAX":        "IMAX",
+	"UHD":         "UHD",
+	"4K":          "UHD",
+	"3D":          "3D",
+}
+
+var regionTokens = map[string]string{
+	"US":     "US",
+	"USA":    "US",
+	"UK":     "UK",
+	"GB":     "UK",
+	"DE":     "DE",
+	"GER":    "DE",
+	"FR":     "FR",
+	"FRA":    "FR",
+	"IT":     "IT",
+	"ITA":    "IT",
+	"ES":     "ES",
+	"ESP":    "ES",
+	"NL":     "NL",
+	"JP":     "JP",
+	"JPN":    "JP",
+	"NORDIC": "Nordic",
+	"PAL":    "PAL",
+	"NTSC":   "NTSC",
+}
+
+// discHints derives edition and region hints from naming conventions used by
+// studios in volume labels, e.g. "MOVIE_EXTENDED_UK". These are guesses and
+// should be presented as such.
+func discHints(disc DiscInfo) []DiscHint {
+	var hints []DiscHint
+	seen := make(map[DiscHint]bool)
+	add := func(hint DiscHint) {
+		if !seen[hint] {
+			seen[hint] = true
+			hints = append(hints, hint)
+		}
+	}
+
+	sources := []struct {
+		name  string
+		value string
+	}{
+		{"volume name", disc.VolumeName},
+		{"disc name", disc.Name},
+	}
+	for _, source := range sources {
+		tokens := strings.FieldsFunc(strings.ToUpper(source.value), func(r rune) bool {
+			return r == '_' || r == '-' || r == ' ' || r == '.'
+		})
+		// the first token is almost always the film itself
+		for i, token := range tokens {
+			if i == 0 {
+				continue
+			}
+			if edition, ok := editionTokens[token]; ok {
+				add(DiscHint{Kind: HintEdition, Value: edition, Source: source.name})
+			}
+			if region, ok := regionTokens[token]; ok {
+				add(DiscHint{Kind: HintRegion, Value: region, Source: source.name})
+			}
+		}
+	}
+	return hints
+}
@@ -0,0 +1,18 @@
+package makemkv
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiscHints(t *testing.T) {
+	hints := discHints(DiscInfo{VolumeName: "MOVIE_EXTENDED_UK", Name: "Movie"})
+	assert.Equal(t, []DiscHint{
+		{Kind: HintEdition, Value: "Extended", Source: "volume name"},
+		{Kind: HintRegion, Value: "UK", Source: "volume name"},
+	}, hints)
+
+	// a film titled after a region token is not a region hint
+	assert.Nil(t, discHints(DiscInfo{VolumeName: "UK"}))
+}
@@ -3,16 +3,42 @@ package makemkv
 import (
 	"bufio"
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
-	"os/exec"
-	"strconv"
-	"strings"
+	"io"
+	"slices"
 	"time"
 )
 
 type InfoJob struct {
+	// Statuschan receives the scan's progress under the same guarantees as
+	// MkvJob's, ending with a Final status. Delivery decides what happens
+	// when the consumer falls behind. Setting it turns on progress output.
+	Statuschan chan Status
+	Delivery   DeliveryPolicy
+	// PhaseTimeouts stops the scan with StopTimeout and a PhaseTimeoutError
+	// when it stays in a phase for too long. Setting it turns on progress
+	// output, which phases are read from.
+	PhaseTimeouts map[ScanPhase]time.Duration
+	// PhaseCodes classifies task codes missing from the package's
+	// PhaseCodes, or overrides them
+	PhaseCodes map[int]ScanPhase
+	// Messagechan receives every MSG line as it is parsed, numbered and
+	// ordered together with Statuschan as on MkvJob. Sends always block.
+	Messagechan chan Message
+	// StartTimeout is as on MkvJob
+	StartTimeout time.Duration
+	// Heartbeatchan receives a Heartbeat every HeartbeatInterval, 30 seconds
+	// when unset, that makemkvcon goes without printing anything. Heartbeats
+	// come from their own goroutine, are dropped when the channel is full
+	// and are all sent before RunContext returns.
+	Heartbeatchan     chan Heartbeat
+	HeartbeatInterval time.Duration
+
 	device  Device
 	options MkvOptions
+	stopper stopper
 }
 
 func Info(device Device, opts MkvOptions) *InfoJob {
@@ -25,11 +51,51 @@ func Info(device Device, opts MkvOptions) *InfoJob {
 type DiscInfo struct {
 	Titles []TitleInfo
 
-	DiscType   string
-	Name       string
-	LangCode   string
-	LangName   string
-	VolumeName string
+	DiscType    string
+	Name        string
+	LangCode    string
+	LangName    string
+	VolumeName  string
+	TreeInfo    string
+	PanelTitle  string
+	Comment     string
+	DateTime    string
+	OrderWeight int
+	Hints       []DiscHint
+	// obfuscation patterns found in the scan, see CompareScans for the ones
+	// that take more than one
+	Anomalies  []Anomaly
+	Version    Version
+	Protection Protection
+	// the device scanned as type:device, set by InfoJob
+	Device string
+	// set by ScanBackup
+	Backup *BackupInfo
+	Report ParseReport
+
+	// every CINFO attribute as emitted, keyed by attribute id, including ones
+	// without a field above
+	RawAttrs map[int]string
+}
+
+func (d *DiscInfo) Attr(id int) (string, bool) {
+	value, ok := d.RawAttrs[id]
+	return value, ok
+}
+
+// AttrIds returns the ids of all the attributes captured for the disc, in
+// ascending order
+func (d *DiscInfo) AttrIds() []int {
+	return attrIds(d.RawAttrs)
+}
+
+func attrIds(attrs map[int]string) []int {
+	ids := make([]int, 0, len(attrs))
+	for id := range attrs {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	return ids
 }
 
 type TitleInfo struct {
@@ -37,311 +103,599 @@ type TitleInfo struct {
 	AudioStreams    []AudioStreamInfo
 	SubtitleStreams []SubtitleStreamInfo
 
-	Id               int
-	Name             string
-	ChapterCount     int
-	Duration         time.Duration
+	Id           int
+	Name         string
+	ChapterCount int
+	Duration     time.Duration
+	// FileSize is the size of the title's content in bytes and DiskSize the
+	// same size as makemkvcon displays it, like "40.4 GB". The output mkv
+	// comes out at roughly FileSize; neither says how much space anything
+	// takes up on a filesystem, see BackupInfo for that.
 	FileSize         int64
+	DiskSize         string
 	SourceFileName   string
 	Segments         []int
 	FileName         string
 	MetadataLangCode string
 	MetadataLangName string
+	AngleInfo        string
+	DateTime         string
+	OriginalTitleId  int
+	SegmentsCount    int
+	TreeInfo         string
+	PanelTitle       string
+	OrderWeight      int
+	// the container written, "mkv", and its description
+	OutputFormat            string
+	OutputFormatDescription string
+	SeamlessInfo            string
+	MkvFlags                string
+	MkvFlagsText            string
+	Comment                 string
+
+	// every TINFO attribute as emitted, keyed by attribute id, including ones
+	// without a field above
+	RawAttrs map[int]string
+}
+
+func (t *TitleInfo) Attr(id int) (string, bool) {
+	value, ok := t.RawAttrs[id]
+	return value, ok
+}
+
+// AttrIds returns the ids of all the attributes captured for the title, in
+// ascending order
+func (t *TitleInfo) AttrIds() []int {
+	return attrIds(t.RawAttrs)
 }
 
 type VideoStreamInfo struct {
-	Id               int
-	Name             string
-	CodecId          string
-	CodecShort       string
-	CodecLong        string
-	VideoSize        string
-	AspectRatio      string
-	FrameRate        string
-	StreamFlags      int
-	MetadataLangCode string
-	MetadataLangName string
-	ConversionType   string
+	// the stream id makemkvcon uses for this stream within its title
+	Id                  int
+	Name                string
+	CodecId             string
+	CodecShort          string
+	CodecLong           string
+	VideoSize           string
+	AspectRatio         string
+	FrameRate           string
+	StreamFlags         int
+	MetadataLangCode    string
+	MetadataLangName    string
+	ConversionType      string
+	OrderWeight         int
+	MkvFlags            string
+	MkvFlagsText        string
+	StreamTypeExtension string
+	OutputCodecShort    string
+	// the MVC offset sequence of a 3D stream
+	OffsetSequenceId int
+
+	// every SINFO attribute as emitted, keyed by attribute id
+	RawAttrs map[int]string
 }
 
 type AudioStreamInfo struct {
-	Id               int
-	Name             string
-	LangCode         string
-	LangName         string
-	CodecId          string
-	CodecShort       string
-	CodecLong        string
-	BitRate          string
-	ChannelCount     int
-	SampleRate       int
-	SampleSize       int
-	StreamFlags      int
-	MetadataLangCode string
-	MetadataLangName string
-	ConversionType   string
+	// the stream id makemkvcon uses for this stream within its title
+	Id                  int
+	Name                string
+	LangCode            string
+	LangName            string
+	CodecId             string
+	CodecShort          string
+	CodecLong           string
+	BitRate             string
+	ChannelCount        int
+	SampleRate          int
+	SampleSize          int
+	StreamFlags         int
+	MetadataLangCode    string
+	MetadataLangName    string
+	ConversionType      string
+	OrderWeight         int
+	MkvFlags            string
+	MkvFlagsText        string
+	StreamTypeExtension string
+	ChannelLayoutName   string
+	OutputCodecShort    string
+	// what the stream is converted to in the mkv, equal to the source values
+	// unless it is downmixed or resampled
+	OutputSampleRate        int
+	OutputSampleSize        int
+	OutputChannelCount      int
+	OutputChannelLayoutName string
+	OutputChannelLayout     int
+	OutputMixDescription    string
+
+	// every SINFO attribute as emitted, keyed by attribute id
+	RawAttrs map[int]string
 }
 
 type SubtitleStreamInfo struct {
-	Id               int
-	Name             string
-	LangCode         string
-	LangName         string
-	CodecId          string
-	CodecShort       string
-	CodecLong        string
-	StreamFlags      int
-	MetadataLangCode string
-	MetadataLangName string
-	ConversionType   string
+	// the stream id makemkvcon uses for this stream within its title
+	Id                  int
+	Name                string
+	LangCode            string
+	LangName            string
+	CodecId             string
+	CodecShort          string
+	CodecLong           string
+	StreamFlags         int
+	MetadataLangCode    string
+	MetadataLangName    string
+	ConversionType      string
+	OrderWeight         int
+	MkvFlags            string
+	MkvFlagsText        string
+	StreamTypeExtension string
+	OutputCodecShort    string
+
+	// every SINFO attribute as emitted, keyed by attribute id
+	RawAttrs map[int]string
 }
 
 func (j *InfoJob) Run() (*DiscInfo, error) {
-	dev := j.device.Type() + ":" + j.device.Device()
-	options := append(j.options.toStrings(), []string{"info", dev}...)
-	cmd := exec.Command("makemkvcon", options...)
+	return j.RunContext(context.Background())
+}
 
-	out, err := cmd.Output()
+// RunContext is Run, stopping the scan when ctx is done. The error is then a
+// StoppedError wrapping ctx.Err(), with StopTimeout as the reason when the
+// deadline passed and StopCanceled otherwise.
+func (j *InfoJob) RunContext(ctx context.Context) (*DiscInfo, error) {
+	defer j.stopper.watch(ctx)()
+	if err := CheckAccess(j.device); err != nil {
+		return nil, err
+	}
+	j.options.Power.begin(j.device, j.options)
+	defer j.options.Power.end(j.device)
+	dev := j.device.Type() + ":" + j.device.Device()
+	opts, err := j.options.withProgress(len(j.PhaseTimeouts) > 0 || j.Statuschan != nil)
+	if err != nil {
+		return nil, err
+	}
+	opts, file, cleanup, err := opts.withTransport()
 	if err != nil {
 		return nil, err
 	}
+	defer cleanup()
+	cmd := newCommand(opts, "info", dev)
+
+	// parse while makemkvcon is still writing rather than buffering the whole
+	// output, so memory use depends on the disc and not on how chatty it is
+	var discInfo DiscInfo
+	var failures failureWatch
+	sender := statusSender{ch: j.Statuschan, policy: j.Delivery}
+	parser := progressParser{status: sender.send}
+	parseErr, err := runCommand(cmd, &j.stopper, file, opts.Audit, func(out io.Reader) error {
+		start := newStartWatch(j.StartTimeout, j.stopper.stopCause)
+		defer start.close()
+		heartbeat := newHeartbeatWatch(j.Heartbeatchan, j.HeartbeatInterval)
+		defer heartbeat.close()
+		observers := []func(prefix []byte, content []byte){start.observe, heartbeat.observe}
+		if len(j.PhaseTimeouts) > 0 {
+			watch := newPhaseWatch(j.PhaseTimeouts, j.PhaseCodes, j.stopper.stopCause)
+			defer watch.close()
+			observers = append(observers, watch.observe)
+		}
+		if j.Statuschan != nil {
+			observers = append(observers, func(prefix []byte, content []byte) {
+				switch kind := parsePrefix(prefix); kind {
+				case prefixPRGT, prefixPRGC, prefixPRGV:
+					parser.parse(kind, string(content), "")
+				}
+			})
+		}
+		observers = append(observers, func(prefix []byte, content []byte) {
+			if !bytes.Equal(prefix, prefixNames[prefixMSG]) {
+				return
+			}
+			if msg, ok := parseMessage(string(content)); ok {
+				failures.observe(msg)
+				heartbeat.observeMessage(msg)
+				if j.Messagechan != nil {
+					msg.Seq = parser.next()
+					j.Messagechan <- msg
+				}
+			}
+		})
+		observe := func(prefix []byte, content []byte) {
+			for _, o := range observers {
+				o(prefix, content)
+			}
+		}
+
+		scanner := bufio.NewScanner(out)
+		var err error
+		if discInfo, err = parseDiscInfoObserved(scanner, observe); err != nil {
+			return err
+		}
+		return scanner.Err()
+	})
+	if j.Statuschan != nil {
+		var reason StopReason
+		var stopped *StoppedError
+		if errors.As(err, &stopped) {
+			reason = stopped.Reason
+		}
+		sender.final(parser.final(reason))
+	}
+	if err != nil {
+		return nil, failures.wrap(err)
+	}
+	if parseErr != nil {
+		return nil, parseErr
+	}
+	discInfo.Device = dev
+	return &discInfo, nil
+}
+
+func (j *InfoJob) Stop(reason StopReason) {
+	j.stopper.stop(reason)
+}
 
-	scanner := bufio.NewScanner(bytes.NewReader(out))
-	if discInfo, err := parseDiscInfo(scanner); err != nil {
+// ParseDiscInfo parses robot output captured from makemkvcon info, without
+// needing makemkvcon at all
+func ParseDiscInfo(r io.Reader) (*DiscInfo, error) {
+	scanner := bufio.NewScanner(r)
+	discInfo, err := parseDiscInfo(scanner)
+	if err != nil {
+		return nil, err
+	}
+	if err := scanner.Err(); err != nil {
 		return nil, err
-	} else {
-		return &discInfo, nil
 	}
+	return &discInfo, nil
 }
 
 func parseDiscInfo(scanner *bufio.Scanner) (DiscInfo, error) {
+	return parseDiscInfoObserved(scanner, nil)
+}
+
+// maxTitleCount bounds the titles a disc may claim, no real disc comes close
+// and anything above it is treated as a corrupt TCOUNT
+const maxTitleCount = 10000
+
+// parseDiscInfoObserved is parseDiscInfo, additionally handing every line to
+// observe (when not nil) before it is parsed
+func parseDiscInfoObserved(scanner *bufio.Scanner, observe func(prefix []byte, content []byte)) (DiscInfo, error) {
 	// since SINFO contains both video and audio, we use these to keep track
-	// of the index offset while parsing, so we can put them in separate slices
-	streamIndices := make(map[int]streamIndex)
+	// of the index offset while parsing, so we can put them in separate
+	// slices. stream ids restart at 0 for every title, so they are keyed
+	// together with the title id
+	streamIndices := make(map[streamKey]streamIndex)
 
 	var discInfo DiscInfo
+	// set when TCOUNT was unusable, titles are then added as their lines
+	// reference them instead of being allocated up front
+	growTitles := false
+	titleAt := func(titleId int) *TitleInfo {
+		if titleId < 0 {
+			return nil
+		}
+		if growTitles && titleId >= len(discInfo.Titles) && titleId < maxTitleCount {
+			for i := len(discInfo.Titles); i <= titleId; i++ {
+				discInfo.Titles = append(discInfo.Titles, TitleInfo{Id: i})
+			}
+		}
+		if titleId >= len(discInfo.Titles) {
+			return nil
+		}
+		return &discInfo.Titles[titleId]
+	}
+	// the last value for an attribute still wins, but differing repeats are
+	// worth knowing about
+	setRaw := func(attrs *map[int]string, titleId, streamId, attrId int, value []byte) {
+		if old, conflict := setRawAttr(attrs, attrId, value); conflict {
+			discInfo.Report.Conflicts = append(discInfo.Report.Conflicts, AttrConflict{
+				TitleId:  titleId,
+				StreamId: streamId,
+				AttrId:   attrId,
+				Old:      old,
+				New:      string(value),
+			})
+		}
+	}
 	for scanner.Scan() {
-		line := scanner.Text()
-		prefix, content, found := strings.Cut(line, ":")
+		// work on the scanner's buffer directly, only values that end up in
+		// the result are copied out into strings
+		line := scanner.Bytes()
+		prefix, content, found := bytes.Cut(line, []byte(":"))
 		if !found {
+			discInfo.Report.line(prefixNone, nil, len(line))
 			continue
 		}
+		kind := parsePrefix(prefix)
+		discInfo.Report.line(kind, prefix, len(line))
+		if observe != nil {
+			observe(prefix, content)
+		}
 
-		switch prefix {
-		case "DRV":
-			continue
-		case "MSG":
+		switch kind {
+		case prefixDRV:
 			continue
+		case prefixMSG:
+			if code, _, _ := cutInt(content); code == msgStarted && discInfo.Version.IsZero() {
+				discInfo.Version, _ = parseVersion(string(content))
+			}
+			if msg, ok := parseMessage(string(content)); ok {
+				discInfo.Protection.observe(msg)
+			}
 
-		case "TCOUNT":
-			size, _ := strconv.Atoi(content)
+		case prefixTCOUNT:
+			size, ok := atoi(content)
+			growTitles = !ok || size < 0 || size > maxTitleCount
+			if growTitles {
+				discInfo.Report.Malformed++
+				size = 0
+			}
 			discInfo.Titles = make([]TitleInfo, size, size)
 			for i := 0; i < size; i++ {
 				discInfo.Titles[i].Id = i
 			}
+			clear(streamIndices)
 
-		case "CINFO":
+		case prefixCINFO:
 			attrId, _, value, ok := parseCinfo(content)
-			if !ok {
+			if !ok || attrId < 0 {
+				discInfo.Report.Malformed++
 				continue
 			}
-			switch attrId {
-			case ap_iaType:
-				discInfo.DiscType = value
-			case ap_iaName:
-				discInfo.Name = value
-			case ap_iaMetadataLanguageCode:
-				discInfo.LangCode = value
-			case ap_iaMetadataLanguageName:
-				discInfo.LangName = value
-			case ap_iaVolumeName:
-				discInfo.VolumeName = value
+			setRaw(&discInfo.RawAttrs, -1, -1, attrId, value)
+			if set := tableAttr(discAttrs, attrId); set != nil {
+				set(&discInfo, value)
 			}
 
-		case "TINFO":
+		case prefixTINFO:
 			titleId, attrId, _, value, ok := parseTinfo(content)
-			if !ok {
+			if !ok || attrId < 0 {
+				discInfo.Report.Malformed++
 				continue
 			}
-			switch attrId {
-			case ap_iaName:
-				discInfo.Titles[titleId].Name = value
-			case ap_iaChapterCount:
-				discInfo.Titles[titleId].ChapterCount, _ = strconv.Atoi(value)
-			case ap_iaDuration:
-				discInfo.Titles[titleId].Duration, _ = parseDuration(value)
-			case ap_iaDiskSizeBytes:
-				discInfo.Titles[titleId].FileSize, _ = strconv.ParseInt(value, 10, 64)
-			case ap_iaSourceFileName:
-				discInfo.Titles[titleId].SourceFileName = value
-			case ap_iaSegmentsCount:
-				if count, err := strconv.Atoi(value); err != nil {
-					discInfo.Titles[titleId].Segments = make([]int, 0, count)
-				}
-			case ap_iaSegmentsMap:
-				if discInfo.Titles[titleId].Segments != nil {
-					for i, s := range strings.Split(value, ",") {
-						discInfo.Titles[titleId].Segments[i], _ = strconv.Atoi(s)
-					}
-				}
-			case ap_iaOutputFileName:
-				discInfo.Titles[titleId].FileName = value
-			case ap_iaMetadataLanguageCode:
-				discInfo.Titles[titleId].MetadataLangCode = value
-			case ap_iaMetadataLanguageName:
-				discInfo.Titles[titleId].MetadataLangName = value
+			title := titleAt(titleId)
+			if title == nil {
+				discInfo.Report.Malformed++
+				continue
+			}
+			setRaw(&title.RawAttrs, titleId, -1, attrId, value)
+			if set := tableAttr(titleAttrs, attrId); set != nil {
+				set(title, value)
 			}
 
-		case "SINFO":
+		case prefixSINFO:
 			titleId, streamId, attrId, _, value, ok := parseSinfo(content)
-			if !ok {
+			if !ok || attrId < 0 {
+				discInfo.Report.Malformed++
+				continue
+			}
+			title := titleAt(titleId)
+			if title == nil {
+				discInfo.Report.Malformed++
+				continue
+			}
+			key := streamKey{titleId, streamId}
+			if old, declared := streamIndices[key]; declared && attrId == ap_iaType {
+				// a stream is declared by its first type line, a second one
+				// doesn't make another stream
+				discInfo.Report.Conflicts = append(discInfo.Report.Conflicts, AttrConflict{
+					TitleId:  titleId,
+					StreamId: streamId,
+					AttrId:   attrId,
+					Old:      old.rawAttrs(title)[ap_iaType],
+					New:      string(value),
+				})
 				continue
 			}
 			if attrId == ap_iaType {
-				var i int
-				switch value {
+				var index streamIndex
+				switch string(value) {
 				case "Video":
-					i = len(discInfo.Titles[titleId].VideoStreams)
-					discInfo.Titles[titleId].VideoStreams = append(discInfo.Titles[titleId].VideoStreams, VideoStreamInfo{Id: streamId})
+					index = streamIndex{StreamVideo, len(title.VideoStreams)}
+					title.VideoStreams = append(title.VideoStreams, VideoStreamInfo{Id: streamId})
 				case "Audio":
-					i = len(discInfo.Titles[titleId].AudioStreams)
-					discInfo.Titles[titleId].AudioStreams = append(discInfo.Titles[titleId].AudioStreams, AudioStreamInfo{Id: streamId})
-				case "Subtitle":
-					i = len(discInfo.Titles[titleId].SubtitleStreams)
-					discInfo.Titles[titleId].SubtitleStreams = append(discInfo.Titles[titleId].SubtitleStreams, SubtitleStreamInfo{Id: streamId})
+					index = streamIndex{StreamAudio, len(title.AudioStreams)}
+					title.AudioStreams = append(title.AudioStreams, AudioStreamInfo{Id: streamId})
+				case "Subtitles", "Subtitle":
+					index = streamIndex{StreamSubtitle, len(title.SubtitleStreams)}
+					title.SubtitleStreams = append(title.SubtitleStreams, SubtitleStreamInfo{Id: streamId})
 				}
-				streamIndices[streamId] = streamIndex{value, i}
-				continue
+				streamIndices[key] = index
 			}
-			var index streamIndex
-			index, _ = streamIndices[streamId]
-			stream := discInfo.Titles[titleId].getStream(index)
-			if stream == nil {
-				continue
-			}
-			switch attrId {
-			case ap_iaName:
-				stream.setName(value)
-			case ap_iaLangCode:
-				stream.setLangCode(value)
-			case ap_iaLangName:
-				stream.setLangName(value)
-			case ap_iaCodecId:
-				stream.setCodecId(value)
-			case ap_iaCodecShort:
-				stream.setCodecShort(value)
-			case ap_iaCodecLong:
-				stream.setCodecLong(value)
-			case ap_iaBitrate:
-				stream.setBitRate(value)
-			case ap_iaAudioChannelsCount:
-				i, _ := strconv.Atoi(value)
-				stream.setChannelCount(i)
-			case ap_iaAudioSampleRate:
-				i, _ := strconv.Atoi(value)
-				stream.setSampleRate(i)
-			case ap_iaAudioSampleSize:
-				i, _ := strconv.Atoi(value)
-				stream.setSampleSize(i)
-			case ap_iaVideoSize:
-				stream.setVideoSize(value)
-			case ap_iaVideoAspectRatio:
-				stream.setAspectRatio(value)
-			case ap_iaVideoFrameRate:
-				stream.setFrameRate(value)
-			case ap_iaStreamFlags:
-				i, _ := strconv.Atoi(value)
-				stream.setStreamFlags(i)
-			case ap_iaMetadataLanguageCode:
-				stream.setMetadataLangCode(value)
-			case ap_iaMetadataLanguageName:
-				stream.setMetadataLangName(value)
-			case ap_iaOutputConversionType:
-				stream.setConversionType(value)
+			index := streamIndices[key]
+			switch index.kind {
+			case StreamVideo:
+				stream := &title.VideoStreams[index.i]
+				setRaw(&stream.RawAttrs, titleId, streamId, attrId, value)
+				if set := tableAttr(videoAttrs, attrId); set != nil {
+					set(stream, value)
+				}
+			case StreamAudio:
+				stream := &title.AudioStreams[index.i]
+				setRaw(&stream.RawAttrs, titleId, streamId, attrId, value)
+				if set := tableAttr(audioAttrs, attrId); set != nil {
+					set(stream, value)
+				}
+			case StreamSubtitle:
+				stream := &title.SubtitleStreams[index.i]
+				setRaw(&stream.RawAttrs, titleId, streamId, attrId, value)
+				if set := tableAttr(subtitleAttrs, attrId); set != nil {
+					set(stream, value)
+				}
 			}
 		}
 	}
 
+	discInfo.Hints = discHints(discInfo)
+	discInfo.Anomalies = discAnomalies(discInfo)
 	return discInfo, nil
 }
 
-func parseDuration(value string) (time.Duration, error) {
-	var h, m, s int
-	if _, err := fmt.Sscanf(value, "%d:%d:%d", &h, &m, &s); err != nil {
-		return 0, err
+func parseDuration(value []byte) (time.Duration, error) {
+	var parts [3]int
+	for i := range parts {
+		var field []byte
+		if i < len(parts)-1 {
+			var found bool
+			field, value, found = bytes.Cut(value, []byte(":"))
+			if !found {
+				return 0, fmt.Errorf("invalid duration %q", field)
+			}
+		} else {
+			field = value
+		}
+		n, ok := atoi(field)
+		if !ok {
+			return 0, fmt.Errorf("invalid duration component %q", field)
+		}
+		parts[i] = n
 	}
-	return time.Duration(h)*time.Hour + time.Duration(m)*time.Minute + time.Duration(s)*time.Second, nil
+	return time.Duration(parts[0])*time.Hour + time.Duration(parts[1])*time.Minute + time.Duration(parts[2])*time.Second, nil
+}
+
+func parseSegments(value []byte) []int {
+	segments := make([]int, 0, bytes.Count(value, []byte(","))+1)
+	for len(value) > 0 {
+		var field []byte
+		field, value, _ = bytes.Cut(value, []byte(","))
+		// contiguous runs are collapsed into ranges like 12-14
+		if lo, hi, found := bytes.Cut(field, []byte("-")); found {
+			start, ok1 := atoi(lo)
+			end, ok2 := atoi(hi)
+			if ok1 && ok2 {
+				for i := start; i <= end; i++ {
+					segments = append(segments, i)
+				}
+			}
+			continue
+		}
+		if i, ok := atoi(field); ok {
+			segments = append(segments, i)
+		}
+	}
+	return segments
+}
+
+// atoi is strconv.Atoi for byte slices, without converting to a string first
+func atoi(b []byte) (int, bool) {
+	if len(b) == 0 {
+		return 0, false
+	}
+	neg := b[0] == '-'
+	if neg {
+		b = b[1:]
+		if len(b) == 0 {
+			return 0, false
+		}
+	}
+	n := 0
+	for _, c := range b {
+		if c < '0' || c > '9' {
+			return 0, false
+		}
+		n = n*10 + int(c-'0')
+	}
+	if neg {
+		n = -n
+	}
+	return n, true
+}
+
+func atoi64(b []byte) int64 {
+	var n int64
+	for _, c := range b {
+		if c < '0' || c > '9' {
+			return 0
+		}
+		n = n*10 + int64(c-'0')
+	}
+	return n
 }
 
-func cutInt(s string, sep string) (int, string, bool) {
-	str, out, found := strings.Cut(s, sep)
+func cutInt(b []byte) (int, []byte, bool) {
+	field, rest, found := bytes.Cut(b, []byte(","))
 	if !found {
-		return 0, str, false
+		return 0, field, false
 	}
-	i, err := strconv.Atoi(str)
-	if err != nil {
-		return i, out, false
+	i, ok := atoi(field)
+	return i, rest, ok
+}
+
+// unquote strips the quotes from the value ending an attribute line, which
+// may hold commas, and undoes makemkvcon's escaping of quotes and backslashes
+func unquote(b []byte) []byte {
+	if len(b) < 2 || b[0] != '"' || b[len(b)-1] != '"' {
+		return b
+	}
+	b = b[1 : len(b)-1]
+	if bytes.IndexByte(b, '\\') < 0 {
+		return b
 	}
-	return i, out, true
+	unescaped := make([]byte, 0, len(b))
+	for i := 0; i < len(b); i++ {
+		if b[i] == '\\' && i+1 < len(b) && (b[i+1] == '"' || b[i+1] == '\\') {
+			i++
+		}
+		unescaped = append(unescaped, b[i])
+	}
+	return unescaped
 }
 
-func parseCinfo(content string) (attrId int, code int, value string, ok bool) {
-	attrId, content, ok = cutInt(content, ",")
+func parseCinfo(content []byte) (attrId int, code int, value []byte, ok bool) {
+	attrId, content, ok = cutInt(content)
 	if !ok {
 		return attrId, code, value, ok
 	}
 
-	code, value, ok = cutInt(content, ",")
+	code, value, ok = cutInt(content)
 	if !ok {
 		return attrId, code, value, ok
 	}
 
-	value = strings.Trim(value, `"`)
+	value = unquote(value)
 	return attrId, code, value, ok
 }
 
-func parseTinfo(content string) (titleId int, attrId int, code int, value string, ok bool) {
-	titleId, content, ok = cutInt(content, ",")
+func parseTinfo(content []byte) (titleId int, attrId int, code int, value []byte, ok bool) {
+	titleId, content, ok = cutInt(content)
 	if !ok {
 		return titleId, attrId, code, value, ok
 	}
 
-	attrId, content, ok = cutInt(content, ",")
+	attrId, content, ok = cutInt(content)
 	if !ok {
 		return titleId, attrId, code, value, ok
 	}
 
-	code, value, ok = cutInt(content, ",")
+	code, value, ok = cutInt(content)
 	if !ok {
 		return titleId, attrId, code, value, ok
 	}
 
-	value = strings.Trim(value, `"`)
+	value = unquote(value)
 	return titleId, attrId, code, value, ok
 }
 
-func parseSinfo(content string) (titleId int, streamId int, attrId int, code int, value string, ok bool) {
-	titleId, content, ok = cutInt(content, ",")
+func parseSinfo(content []byte) (titleId int, streamId int, attrId int, code int, value []byte, ok bool) {
+	titleId, content, ok = cutInt(content)
 	if !ok {
 		return titleId, streamId, attrId, code, value, ok
 	}
 
-	streamId, content, ok = cutInt(content, ",")
+	streamId, content, ok = cutInt(content)
 	if !ok {
 		return titleId, streamId, attrId, code, value, ok
 	}
 
-	attrId, content, ok = cutInt(content, ",")
+	attrId, content, ok = cutInt(content)
 	if !ok {
 		return titleId, streamId, attrId, code, value, ok
 	}
 
-	code, value, ok = cutInt(content, ",")
+	code, value, ok = cutInt(content)
 	if !ok {
 		return titleId, streamId, attrId, code, value, ok
 	}
 
-	value = strings.Trim(value, `"`)
+	value = unquote(value)
 	return titleId, streamId, attrId, code, value, ok
 }
 
@@ -399,263 +753,160 @@ const (
 	ap_iaOutputAudioMixDescription        = 48
 	ap_iaComment                          = 49
 	ap_iaOffsetSequenceId                 = 50
-	ap_iaMaxValue
+	ap_iaMaxValue                         = 51
 )
 
-//////////////////////////// hack ////////////////////////////
-// janky abstraction to simplify video/audio stream parsing //
-
-type streamIndex struct {
-	t string
-	i int
-}
-
-func (t *TitleInfo) getStream(index streamIndex) iStreamInfo {
-	switch index.t {
-	case "Video":
-		return &t.VideoStreams[index.i]
-	case "Audio":
-		return &t.AudioStreams[index.i]
-	case "Subtitle":
-		return &t.SubtitleStreams[index.i]
-	default:
-		return nil
-	}
-}
-
-type iStreamInfo interface {
-	setId(int)
-	setName(string)
-	setLangCode(string)
-	setLangName(string)
-	setCodecId(string)
-	setCodecShort(string)
-	setCodecLong(string)
-	setBitRate(string)
-	setChannelCount(int)
-	setSampleRate(int)
-	setSampleSize(int)
-	setVideoSize(string)
-	setAspectRatio(string)
-	setFrameRate(string)
-	setStreamFlags(int)
-	setMetadataLangCode(string)
-	setMetadataLangName(string)
-	setConversionType(string)
-}
+/////////////////////// attribute tables ///////////////////////
+// which attribute ids land in which field, indexed by attribute id //
 
-func (v *VideoStreamInfo) setId(id int) {
-	v.Id = id
+type streamKey struct {
+	titleId  int
+	streamId int
 }
 
-func (v *VideoStreamInfo) setName(name string) {
-	v.Name = name
-}
-
-func (v *VideoStreamInfo) setLangCode(langCode string) {
-	// nop
-}
-
-func (v *VideoStreamInfo) setLangName(langName string) {
-	// nop
-}
-
-func (v *VideoStreamInfo) setCodecId(codecId string) {
-	v.CodecId = codecId
-}
-
-func (v *VideoStreamInfo) setCodecShort(codecShort string) {
-	v.CodecShort = codecShort
-}
-
-func (v *VideoStreamInfo) setCodecLong(codecLong string) {
-	v.CodecLong = codecLong
-}
-
-func (v *VideoStreamInfo) setBitRate(bitRate string) {
-	// nop
-}
-
-func (v *VideoStreamInfo) setChannelCount(channelCount int) {
-	// nop
-}
-
-func (v *VideoStreamInfo) setSampleRate(sampleRate int) {
-	// nop
-}
-
-func (v *VideoStreamInfo) setSampleSize(sampleSize int) {
-	// nop
-}
-
-func (v *VideoStreamInfo) setVideoSize(videoSize string) {
-	v.VideoSize = videoSize
-}
-
-func (v *VideoStreamInfo) setAspectRatio(aspectRatio string) {
-	v.AspectRatio = aspectRatio
-}
-
-func (v *VideoStreamInfo) setFrameRate(frameRate string) {
-	v.FrameRate = frameRate
-}
-
-func (v *VideoStreamInfo) setStreamFlags(streamFlags int) {
-	v.StreamFlags = streamFlags
-}
-
-func (v *VideoStreamInfo) setMetadataLangCode(metadataLangCode string) {
-	v.MetadataLangCode = metadataLangCode
-}
-
-func (v *VideoStreamInfo) setMetadataLangName(metadataLangName string) {
-	v.MetadataLangName = metadataLangName
-}
-
-func (v *VideoStreamInfo) setConversionType(conversionType string) {
-	v.ConversionType = conversionType
-}
-
-func (a *AudioStreamInfo) setId(id int) {
-	a.Id = id
-}
-
-func (a *AudioStreamInfo) setName(name string) {
-	a.Name = name
-}
-
-func (a *AudioStreamInfo) setLangCode(langCode string) {
-	a.LangCode = langCode
-}
-
-func (a *AudioStreamInfo) setLangName(langName string) {
-	a.LangName = langName
-}
-
-func (a *AudioStreamInfo) setCodecId(codecId string) {
-	a.CodecId = codecId
-}
-
-func (a *AudioStreamInfo) setCodecShort(codecShort string) {
-	a.CodecShort = codecShort
-}
-
-func (a *AudioStreamInfo) setCodecLong(codecLong string) {
-	a.CodecLong = codecLong
-}
-
-func (a *AudioStreamInfo) setBitRate(bitRate string) {
-	a.BitRate = bitRate
-}
-
-func (a *AudioStreamInfo) setChannelCount(channelCount int) {
-	a.ChannelCount = channelCount
-}
-
-func (a *AudioStreamInfo) setSampleRate(sampleRate int) {
-	a.SampleRate = sampleRate
-}
-
-func (a *AudioStreamInfo) setSampleSize(sampleSize int) {
-	a.SampleSize = sampleSize
-}
-
-func (a *AudioStreamInfo) setVideoSize(videoSize string) {
-	// nop
-}
-
-func (a *AudioStreamInfo) setAspectRatio(aspectRatio string) {
-	// nop
-}
-
-func (a *AudioStreamInfo) setFrameRate(frameRate string) {
-	// nop
-}
-
-func (a *AudioStreamInfo) setStreamFlags(streamFlags int) {
-	a.StreamFlags = streamFlags
-}
-
-func (a *AudioStreamInfo) setMetadataLangCode(metadataLangCode string) {
-	a.MetadataLangCode = metadataLangCode
-}
-
-func (a *AudioStreamInfo) setMetadataLangName(metadataLangName string) {
-	a.MetadataLangName = metadataLangName
-}
-
-func (a *AudioStreamInfo) setConversionType(conversionType string) {
-	a.ConversionType = conversionType
-}
-
-func (s *SubtitleStreamInfo) setId(id int) {
-	s.Id = id
-}
-
-func (a *SubtitleStreamInfo) setName(name string) {
-	a.Name = name
-}
-
-func (a *SubtitleStreamInfo) setLangCode(langCode string) {
-	a.LangCode = langCode
-}
-
-func (a *SubtitleStreamInfo) setLangName(langName string) {
-	a.LangName = langName
-}
-
-func (a *SubtitleStreamInfo) setCodecId(codecId string) {
-	a.CodecId = codecId
-}
-
-func (a *SubtitleStreamInfo) setCodecShort(codecShort string) {
-	a.CodecShort = codecShort
-}
-
-func (a *SubtitleStreamInfo) setCodecLong(codecLong string) {
-	a.CodecLong = codecLong
-}
-
-func (a *SubtitleStreamInfo) setBitRate(bitRate string) {
-	// nop
-}
-
-func (a *SubtitleStreamInfo) setChannelCount(channelCount int) {
-	// nop
-}
-
-func (a *SubtitleStreamInfo) setSampleRate(sampleRate int) {
-	// nop
-}
-
-func (a *SubtitleStreamInfo) setSampleSize(sampleSize int) {
-	// nop
-}
-
-func (a *SubtitleStreamInfo) setVideoSize(videoSize string) {
-	// nop
-}
-
-func (a *SubtitleStreamInfo) setAspectRatio(aspectRatio string) {
-	// nop
-}
-
-func (a *SubtitleStreamInfo) setFrameRate(frameRate string) {
-	// nop
-}
-
-func (a *SubtitleStreamInfo) setStreamFlags(streamFlags int) {
-	a.StreamFlags = streamFlags
-}
-
-func (a *SubtitleStreamInfo) setMetadataLangCode(metadataLangCode string) {
-	a.MetadataLangCode = metadataLangCode
+type streamIndex struct {
+	kind StreamKind
+	i    int
+}
+
+// rawAttrs returns the RawAttrs of the stream in title that index points at,
+// nil for streams of unknown kind which aren't kept
+func (index streamIndex) rawAttrs(title *TitleInfo) map[int]string {
+	switch index.kind {
+	case StreamVideo:
+		return title.VideoStreams[index.i].RawAttrs
+	case StreamAudio:
+		return title.AudioStreams[index.i].RawAttrs
+	case StreamSubtitle:
+		return title.SubtitleStreams[index.i].RawAttrs
+	}
+	return nil
 }
 
-func (a *SubtitleStreamInfo) setMetadataLangName(metadataLangName string) {
-	a.MetadataLangName = metadataLangName
+// setRawAttr stores value under id, returning the value it replaced when
+// that was different
+func setRawAttr(attrs *map[int]string, id int, value []byte) (string, bool) {
+	if *attrs == nil {
+		*attrs = make(map[int]string)
+	}
+	old, seen := (*attrs)[id]
+	if seen && old == string(value) {
+		return "", false
+	}
+	(*attrs)[id] = string(value)
+	return old, seen
 }
 
-func (a *SubtitleStreamInfo) setConversionType(conversionType string) {
-	a.ConversionType = conversionType
+// tableAttr looks up the setter for id, nil for ids past the end of the table
+func tableAttr[T any](table [ap_iaMaxValue]func(*T, []byte), id int) func(*T, []byte) {
+	if id >= len(table) {
+		return nil
+	}
+	return table[id]
+}
+
+var discAttrs = [ap_iaMaxValue]func(*DiscInfo, []byte){
+	ap_iaType:                 func(d *DiscInfo, v []byte) { d.DiscType = string(v) },
+	ap_iaName:                 func(d *DiscInfo, v []byte) { d.Name = string(v) },
+	ap_iaMetadataLanguageCode: func(d *DiscInfo, v []byte) { d.LangCode = string(v) },
+	ap_iaMetadataLanguageName: func(d *DiscInfo, v []byte) { d.LangName = string(v) },
+	ap_iaVolumeName:           func(d *DiscInfo, v []byte) { d.VolumeName = string(v) },
+	ap_iaTreeInfo:             func(d *DiscInfo, v []byte) { d.TreeInfo = string(v) },
+	ap_iaPanelTitle:           func(d *DiscInfo, v []byte) { d.PanelTitle = string(v) },
+	ap_iaComment:              func(d *DiscInfo, v []byte) { d.Comment = string(v) },
+	ap_iaDateTime:             func(d *DiscInfo, v []byte) { d.DateTime = string(v) },
+	ap_iaOrderWeight:          func(d *DiscInfo, v []byte) { d.OrderWeight, _ = atoi(v) },
+}
+
+var titleAttrs = [ap_iaMaxValue]func(*TitleInfo, []byte){
+	ap_iaName:                    func(t *TitleInfo, v []byte) { t.Name = string(v) },
+	ap_iaChapterCount:            func(t *TitleInfo, v []byte) { t.ChapterCount, _ = atoi(v) },
+	ap_iaDuration:                func(t *TitleInfo, v []byte) { t.Duration, _ = parseDuration(v) },
+	ap_iaDiskSize:                func(t *TitleInfo, v []byte) { t.DiskSize = string(v) },
+	ap_iaDiskSizeBytes:           func(t *TitleInfo, v []byte) { t.FileSize = atoi64(v) },
+	ap_iaSourceFileName:          func(t *TitleInfo, v []byte) { t.SourceFileName = string(v) },
+	ap_iaSegmentsMap:             func(t *TitleInfo, v []byte) { t.Segments = parseSegments(v) },
+	ap_iaOutputFileName:          func(t *TitleInfo, v []byte) { t.FileName = string(v) },
+	ap_iaMetadataLanguageCode:    func(t *TitleInfo, v []byte) { t.MetadataLangCode = string(v) },
+	ap_iaMetadataLanguageName:    func(t *TitleInfo, v []byte) { t.MetadataLangName = string(v) },
+	ap_iaAngleInfo:               func(t *TitleInfo, v []byte) { t.AngleInfo = string(v) },
+	ap_iaDateTime:                func(t *TitleInfo, v []byte) { t.DateTime = string(v) },
+	ap_iaOriginalTitleId:         func(t *TitleInfo, v []byte) { t.OriginalTitleId, _ = atoi(v) },
+	ap_iaSegmentsCount:           func(t *TitleInfo, v []byte) { t.SegmentsCount, _ = atoi(v) },
+	ap_iaTreeInfo:                func(t *TitleInfo, v []byte) { t.TreeInfo = string(v) },
+	ap_iaPanelTitle:              func(t *TitleInfo, v []byte) { t.PanelTitle = string(v) },
+	ap_iaOrderWeight:             func(t *TitleInfo, v []byte) { t.OrderWeight, _ = atoi(v) },
+	ap_iaOutputFormat:            func(t *TitleInfo, v []byte) { t.OutputFormat = string(v) },
+	ap_iaOutputFormatDescription: func(t *TitleInfo, v []byte) { t.OutputFormatDescription = string(v) },
+	ap_iaSeamlessInfo:            func(t *TitleInfo, v []byte) { t.SeamlessInfo = string(v) },
+	ap_iaMkvFlags:                func(t *TitleInfo, v []byte) { t.MkvFlags = string(v) },
+	ap_iaMkvFlagsText:            func(t *TitleInfo, v []byte) { t.MkvFlagsText = string(v) },
+	ap_iaComment:                 func(t *TitleInfo, v []byte) { t.Comment = string(v) },
+}
+
+var videoAttrs = [ap_iaMaxValue]func(*VideoStreamInfo, []byte){
+	ap_iaName:                 func(s *VideoStreamInfo, v []byte) { s.Name = string(v) },
+	ap_iaCodecId:              func(s *VideoStreamInfo, v []byte) { s.CodecId = string(v) },
+	ap_iaCodecShort:           func(s *VideoStreamInfo, v []byte) { s.CodecShort = string(v) },
+	ap_iaCodecLong:            func(s *VideoStreamInfo, v []byte) { s.CodecLong = string(v) },
+	ap_iaVideoSize:            func(s *VideoStreamInfo, v []byte) { s.VideoSize = string(v) },
+	ap_iaVideoAspectRatio:     func(s *VideoStreamInfo, v []byte) { s.AspectRatio = string(v) },
+	ap_iaVideoFrameRate:       func(s *VideoStreamInfo, v []byte) { s.FrameRate = string(v) },
+	ap_iaStreamFlags:          func(s *VideoStreamInfo, v []byte) { s.StreamFlags, _ = atoi(v) },
+	ap_iaMetadataLanguageCode: func(s *VideoStreamInfo, v []byte) { s.MetadataLangCode = string(v) },
+	ap_iaMetadataLanguageName: func(s *VideoStreamInfo, v []byte) { s.MetadataLangName = string(v) },
+	ap_iaOutputConversionType: func(s *VideoStreamInfo, v []byte) { s.ConversionType = string(v) },
+	ap_iaOrderWeight:          func(s *VideoStreamInfo, v []byte) { s.OrderWeight, _ = atoi(v) },
+	ap_iaMkvFlags:             func(s *VideoStreamInfo, v []byte) { s.MkvFlags = string(v) },
+	ap_iaMkvFlagsText:         func(s *VideoStreamInfo, v []byte) { s.MkvFlagsText = string(v) },
+	ap_iaStreamTypeExtension:  func(s *VideoStreamInfo, v []byte) { s.StreamTypeExtension = string(v) },
+	ap_iaOutputCodecShort:     func(s *VideoStreamInfo, v []byte) { s.OutputCodecShort = string(v) },
+	ap_iaOffsetSequenceId:     func(s *VideoStreamInfo, v []byte) { s.OffsetSequenceId, _ = atoi(v) },
+}
+
+var audioAttrs = [ap_iaMaxValue]func(*AudioStreamInfo, []byte){
+	ap_iaName:                         func(s *AudioStreamInfo, v []byte) { s.Name = string(v) },
+	ap_iaLangCode:                     func(s *AudioStreamInfo, v []byte) { s.LangCode = string(v) },
+	ap_iaLangName:                     func(s *AudioStreamInfo, v []byte) { s.LangName = string(v) },
+	ap_iaCodecId:                      func(s *AudioStreamInfo, v []byte) { s.CodecId = string(v) },
+	ap_iaCodecShort:                   func(s *AudioStreamInfo, v []byte) { s.CodecShort = string(v) },
+	ap_iaCodecLong:                    func(s *AudioStreamInfo, v []byte) { s.CodecLong = string(v) },
+	ap_iaBitrate:                      func(s *AudioStreamInfo, v []byte) { s.BitRate = string(v) },
+	ap_iaAudioChannelsCount:           func(s *AudioStreamInfo, v []byte) { s.ChannelCount, _ = atoi(v) },
+	ap_iaAudioSampleRate:              func(s *AudioStreamInfo, v []byte) { s.SampleRate, _ = atoi(v) },
+	ap_iaAudioSampleSize:              func(s *AudioStreamInfo, v []byte) { s.SampleSize, _ = atoi(v) },
+	ap_iaStreamFlags:                  func(s *AudioStreamInfo, v []byte) { s.StreamFlags, _ = atoi(v) },
+	ap_iaMetadataLanguageCode:         func(s *AudioStreamInfo, v []byte) { s.MetadataLangCode = string(v) },
+	ap_iaMetadataLanguageName:         func(s *AudioStreamInfo, v []byte) { s.MetadataLangName = string(v) },
+	ap_iaOutputConversionType:         func(s *AudioStreamInfo, v []byte) { s.ConversionType = string(v) },
+	ap_iaOrderWeight:                  func(s *AudioStreamInfo, v []byte) { s.OrderWeight, _ = atoi(v) },
+	ap_iaMkvFlags:                     func(s *AudioStreamInfo, v []byte) { s.MkvFlags = string(v) },
+	ap_iaMkvFlagsText:                 func(s *AudioStreamInfo, v []byte) { s.MkvFlagsText = string(v) },
+	ap_iaStreamTypeExtension:          func(s *AudioStreamInfo, v []byte) { s.StreamTypeExtension = string(v) },
+	ap_iaOutputCodecShort:             func(s *AudioStreamInfo, v []byte) { s.OutputCodecShort = string(v) },
+	ap_iaAudioChannelLayoutName:       func(s *AudioStreamInfo, v []byte) { s.ChannelLayoutName = string(v) },
+	ap_iaOutputAudioSampleRate:        func(s *AudioStreamInfo, v []byte) { s.OutputSampleRate, _ = atoi(v) },
+	ap_iaOutputAudioSampleSize:        func(s *AudioStreamInfo, v []byte) { s.OutputSampleSize, _ = atoi(v) },
+	ap_iaOutputAudioChannelsCount:     func(s *AudioStreamInfo, v []byte) { s.OutputChannelCount, _ = atoi(v) },
+	ap_iaOutputAudioChannelLayoutName: func(s *AudioStreamInfo, v []byte) { s.OutputChannelLayoutName = string(v) },
+	ap_iaOutputAudioChannelLayout:     func(s *AudioStreamInfo, v []byte) { s.OutputChannelLayout, _ = atoi(v) },
+	ap_iaOutputAudioMixDescription:    func(s *AudioStreamInfo, v []byte) { s.OutputMixDescription = string(v) },
+}
+
+var subtitleAttrs = [ap_iaMaxValue]func(*SubtitleStreamInfo, []byte){
+	ap_iaName:                 func(s *SubtitleStreamInfo, v []byte) { s.Name = string(v) },
+	ap_iaLangCode:             func(s *SubtitleStreamInfo, v []byte) { s.LangCode = string(v) },
+	ap_iaLangName:             func(s *SubtitleStreamInfo, v []byte) { s.LangName = string(v) },
+	ap_iaCodecId:              func(s *SubtitleStreamInfo, v []byte) { s.CodecId = string(v) },
+	ap_iaCodecShort:           func(s *SubtitleStreamInfo, v []byte) { s.CodecShort = string(v) },
+	ap_iaCodecLong:            func(s *SubtitleStreamInfo, v []byte) { s.CodecLong = string(v) },
+	ap_iaStreamFlags:          func(s *SubtitleStreamInfo, v []byte) { s.StreamFlags, _ = atoi(v) },
+	ap_iaMetadataLanguageCode: func(s *SubtitleStreamInfo, v []byte) { s.MetadataLangCode = string(v) },
+	ap_iaMetadataLanguageName: func(s *SubtitleStreamInfo, v []byte) { s.MetadataLangName = string(v) },
+	ap_iaOutputConversionType: func(s *SubtitleStreamInfo, v []byte) { s.ConversionType = string(v) },
+	ap_iaOrderWeight:          func(s *SubtitleStreamInfo, v []byte) { s.OrderWeight, _ = atoi(v) },
+	ap_iaMkvFlags:             func(s *SubtitleStreamInfo, v []byte) { s.MkvFlags = string(v) },
+	ap_iaMkvFlagsText:         func(s *SubtitleStreamInfo, v []byte) { s.MkvFlagsText = string(v) },
+	ap_iaStreamTypeExtension:  func(s *SubtitleStreamInfo, v []byte) { s.StreamTypeExtension = string(v) },
+	ap_iaOutputCodecShort:     func(s *SubtitleStreamInfo, v []byte) { s.OutputCodecShort = string(v) },
 }
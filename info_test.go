@@ -2,8 +2,11 @@ package makemkv
 
 import (
 	"bufio"
+	"fmt"
+	"strconv"
 	"strings"
 	"testing"
+	"testing/iotest"
 	"time"
 
 	"github.com/stretchr/testify/assert"
@@ -18,6 +21,28 @@ func TestParseDiscInfo(t *testing.T) {
 	assert.Equal(t, "LangCode", result.LangCode)
 	assert.Equal(t, "LangName", result.LangName)
 	assert.Equal(t, "VolumeName", result.VolumeName)
+	assert.Equal(t, "DiscTreeInfo", result.TreeInfo)
+	assert.Equal(t, "<b>Source information</b><br>", result.PanelTitle)
+	assert.Equal(t, 0, result.OrderWeight)
+	if value, ok := result.Attr(ap_iaVolumeName); assert.True(t, ok) {
+		assert.Equal(t, "VolumeName", value)
+	}
+	if value, ok := result.Attr(99); assert.True(t, ok) {
+		assert.Equal(t, "FutureAttr", value)
+	}
+	_, ok := result.Attr(ap_iaComment)
+	assert.False(t, ok)
+	if value, ok := result.Titles[0].Attr(99); assert.True(t, ok) {
+		assert.Equal(t, "FutureTitleAttr", value)
+	}
+	if value, ok := result.Titles[0].Attr(ap_iaName); assert.True(t, ok) {
+		assert.Equal(t, "TitleName0", value)
+	}
+	titleIds := result.Titles[0].AttrIds()
+	assert.Equal(t, ap_iaName, titleIds[0])
+	assert.Equal(t, 99, titleIds[len(titleIds)-1])
+	assert.Equal(t, len(result.Titles[0].RawAttrs), len(titleIds))
+	assert.Equal(t, Version{Major: 1, Minor: 17, Patch: 6}, result.Version)
 	assert.Equal(t, 3, len(result.Titles), "Titles length does not match")
 	assertTitle(t, TitleInfo{
 		VideoStreams:     make([]VideoStreamInfo, 1),
@@ -27,6 +52,7 @@ func TestParseDiscInfo(t *testing.T) {
 		ChapterCount:     42,
 		Duration:         1*time.Hour + 32*time.Minute + 31*time.Second,
 		FileSize:         12345,
+		DiskSize:         "40.4 GB",
 		SourceFileName:   "00000.mpls",
 		Segments:         []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10},
 		FileName:         "TitleName0_t00.mkv",
@@ -41,6 +67,7 @@ func TestParseDiscInfo(t *testing.T) {
 		ChapterCount:     42,
 		Duration:         1*time.Hour + 32*time.Minute + 31*time.Second,
 		FileSize:         23456,
+		DiskSize:         "40.3 GB",
 		SourceFileName:   "00002.mpls",
 		Segments:         []int{1, 2, 3, 4, 5, 6, 7, 8, 9},
 		FileName:         "TitleName1_t01.mkv",
@@ -55,6 +82,7 @@ func TestParseDiscInfo(t *testing.T) {
 		ChapterCount:     42,
 		Duration:         1*time.Hour + 32*time.Minute + 31*time.Second,
 		FileSize:         34567,
+		DiskSize:         "40.3 GB",
 		SourceFileName:   "00001.mpls",
 		Segments:         []int{3, 4, 5, 6, 7, 8, 9, 10},
 		FileName:         "TitleName2_t02.mkv",
@@ -63,15 +91,116 @@ func TestParseDiscInfo(t *testing.T) {
 	}, result.Titles[2])
 }
 
+func TestParseDiscInfoStreamIds(t *testing.T) {
+	// attribute lines for a title's streams may arrive after another title's
+	// streams with the same ids, and a stream with no type line is ignored
+	scanner := bufio.NewScanner(strings.NewReader(`TCOUNT:2
+SINFO:0,0,1,6201,"Video"
+SINFO:0,1,1,6202,"Audio"
+SINFO:1,0,1,6202,"Audio"
+SINFO:1,1,1,6203,"Subtitles"
+SINFO:0,1,3,0,"eng"
+SINFO:1,0,3,0,"fra"
+SINFO:1,1,3,0,"deu"
+SINFO:1,2,3,0,"spa"
+`))
+	result, err := parseDiscInfo(scanner)
+	assert.Nil(t, err)
+	assert.Equal(t, []AudioStreamInfo{{Id: 1, LangCode: "eng", RawAttrs: map[int]string{1: "Audio", 3: "eng"}}}, result.Titles[0].AudioStreams)
+	assert.Equal(t, []AudioStreamInfo{{Id: 0, LangCode: "fra", RawAttrs: map[int]string{1: "Audio", 3: "fra"}}}, result.Titles[1].AudioStreams)
+	assert.Equal(t, []SubtitleStreamInfo{{Id: 1, LangCode: "deu", RawAttrs: map[int]string{1: "Subtitles", 3: "deu"}}}, result.Titles[1].SubtitleStreams)
+}
+
+func TestParseSegments(t *testing.T) {
+	assert.Equal(t, []int{1, 2, 3}, parseSegments([]byte("1,2,3")))
+	assert.Equal(t, []int{1, 4, 5, 6, 9}, parseSegments([]byte("1,4-6,9")))
+	assert.Equal(t, []int{}, parseSegments([]byte("")))
+}
+
+func TestParseDuration(t *testing.T) {
+	d, err := parseDuration([]byte("1:32:31"))
+	assert.Nil(t, err)
+	assert.Equal(t, 1*time.Hour+32*time.Minute+31*time.Second, d)
+	_, err = parseDuration([]byte("1:32"))
+	assert.NotNil(t, err)
+}
+
+// memory per op should track the size of the resulting DiscInfo, not the
+// ~200k lines of output it was parsed from
+func BenchmarkParseDiscInfo(b *testing.B) {
+	large := syntheticInfo(250, 50)
+	b.SetBytes(int64(len(large)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		scanner := bufio.NewScanner(strings.NewReader(large))
+		if _, err := parseDiscInfo(scanner); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// syntheticInfo builds robot output for a disc with the given number of
+// titles and streams per title, cycling through the streams of the first
+// title in input
+func syntheticInfo(titles int, streams int) string {
+	var cinfo, tinfo []string
+	var sinfo [][]string
+	for _, line := range strings.Split(input, "\n") {
+		if strings.HasPrefix(line, "CINFO:") {
+			cinfo = append(cinfo, line)
+		} else if content, ok := strings.CutPrefix(line, "TINFO:0,"); ok {
+			tinfo = append(tinfo, content)
+		} else if content, ok := strings.CutPrefix(line, "SINFO:0,"); ok {
+			id, attr, _ := strings.Cut(content, ",")
+			if id == strconv.Itoa(len(sinfo)) {
+				sinfo = append(sinfo, nil)
+			}
+			sinfo[len(sinfo)-1] = append(sinfo[len(sinfo)-1], attr)
+		}
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "TCOUNT:%d\n", titles)
+	for _, line := range cinfo {
+		sb.WriteString(line + "\n")
+	}
+	for t := 0; t < titles; t++ {
+		for _, content := range tinfo {
+			fmt.Fprintf(&sb, "TINFO:%d,%s\n", t, content)
+		}
+		for s := 0; s < streams; s++ {
+			for _, attr := range sinfo[s%len(sinfo)] {
+				fmt.Fprintf(&sb, "SINFO:%d,%d,%s\n", t, s, attr)
+			}
+		}
+	}
+	return sb.String()
+}
+
+func TestParseDiscInfoStreamSelection(t *testing.T) {
+	scanner := bufio.NewScanner(strings.NewReader(input))
+	result, err := parseDiscInfo(scanner)
+	assert.Nil(t, err)
+	audio := result.Titles[0].AudioStreams
+	assert.Equal(t, 90, audio[0].OrderWeight)
+	assert.Equal(t, "d", audio[0].MkvFlags)
+	assert.Equal(t, "Default", audio[0].MkvFlagsText)
+	assert.True(t, audio[0].MkvDefault())
+	assert.False(t, audio[1].MkvDefault())
+}
+
 func assertTitle(t *testing.T, expected TitleInfo, actual TitleInfo) {
 	assert.Equal(t, len(expected.AudioStreams), len(actual.AudioStreams), "AudioStream length does not match")
 	assert.Equal(t, len(expected.VideoStreams), len(actual.VideoStreams), "VideoStream length does not match")
+	assert.Equal(t, len(expected.SubtitleStreams), len(actual.SubtitleStreams), "SubtitleStream length does not match")
 	assert.Equal(t, expected.Name, actual.Name)
 	assert.Equal(t, expected.ChapterCount, actual.ChapterCount)
 	assert.Equal(t, expected.Duration, actual.Duration)
 	assert.Equal(t, expected.FileSize, actual.FileSize)
+	assert.Equal(t, expected.DiskSize, actual.DiskSize)
 	assert.Equal(t, expected.SourceFileName, actual.SourceFileName)
-	// assert.Equal(t, expected.Segments, actual.Segments)
+	assert.Equal(t, expected.Segments, actual.Segments)
 	assert.Equal(t, expected.FileName, actual.FileName)
 	assert.Equal(t, expected.MetadataLangCode, actual.MetadataLangCode)
 	assert.Equal(t, expected.MetadataLangName, actual.MetadataLangName)
@@ -96,7 +225,9 @@ CINFO:30,0,"DiscTreeInfo"
 CINFO:31,6119,"<b>Source information</b><br>"
 CINFO:32,0,"VolumeName"
 CINFO:33,0,"0"
+CINFO:99,0,"FutureAttr"
 TINFO:0,2,0,"TitleName0"
+TINFO:0,99,0,"FutureTitleAttr"
 TINFO:0,8,0,"42"
 TINFO:0,9,0,"1:32:31"
 TINFO:0,10,0,"40.4 GB"
@@ -309,3 +440,135 @@ SINFO:2,1,39,0,"Default"
 SINFO:2,1,40,0,"7.1"
 SINFO:2,1,42,5088,"ConversionType"
 `
+
+func TestParseDiscInfoConflicts(t *testing.T) {
+	scanner := bufio.NewScanner(strings.NewReader(`TCOUNT:1
+CINFO:2,0,"Disc"
+CINFO:2,0,"Disc"
+TINFO:0,2,0,"First"
+TINFO:0,2,0,"Second"
+SINFO:0,0,1,6202,"Audio"
+SINFO:0,0,3,0,"eng"
+SINFO:0,0,3,0,"fra"
+SINFO:0,0,1,6202,"Audio"
+SINFO:0,0,3,0,"ger"
+`))
+	result, err := parseDiscInfo(scanner)
+	assert.Nil(t, err)
+	assert.Equal(t, "Second", result.Titles[0].Name)
+	// the repeated type line doesn't start another stream
+	assert.Len(t, result.Titles[0].AudioStreams, 1)
+	assert.Equal(t, "ger", result.Titles[0].AudioStreams[0].LangCode)
+	assert.Equal(t, []AttrConflict{
+		{TitleId: 0, StreamId: -1, AttrId: ap_iaName, Old: "First", New: "Second"},
+		{TitleId: 0, StreamId: 0, AttrId: ap_iaLangCode, Old: "eng", New: "fra"},
+		{TitleId: 0, StreamId: 0, AttrId: ap_iaType, Old: "Audio", New: "Audio"},
+		{TitleId: 0, StreamId: 0, AttrId: ap_iaLangCode, Old: "fra", New: "ger"},
+	}, result.Report.Conflicts)
+}
+
+func TestParseDiscInfoReader(t *testing.T) {
+	result, err := ParseDiscInfo(strings.NewReader(input))
+	assert.Nil(t, err)
+	assert.Equal(t, "DiscName", result.Name)
+	assert.Equal(t, 3, len(result.Titles))
+
+	_, err = ParseDiscInfo(iotest.ErrReader(assert.AnError))
+	assert.ErrorIs(t, err, assert.AnError)
+}
+
+func TestParseDiscInfoEscapes(t *testing.T) {
+	result, err := ParseDiscInfo(strings.NewReader(`TCOUNT:1
+CINFO:2,0,"Movie, The \"Director's Cut\""
+TINFO:0,2,0,"Part 1, \\ Part 2"
+`))
+	assert.Nil(t, err)
+	assert.Equal(t, `Movie, The "Director's Cut"`, result.Name)
+	if assert.Equal(t, 1, len(result.Titles)) {
+		assert.Equal(t, `Part 1, \ Part 2`, result.Titles[0].Name)
+	}
+}
+
+func TestParseDiscInfoRemainingAttrs(t *testing.T) {
+	result, err := ParseDiscInfo(strings.NewReader(`TCOUNT:1
+TINFO:0,15,0,"2"
+TINFO:0,24,0,"7"
+TINFO:0,25,0,"3"
+TINFO:0,30,0,"TitleTree"
+TINFO:0,31,0,"TitlePanel"
+TINFO:0,33,0,"10"
+TINFO:0,34,0,"mkv"
+TINFO:0,35,0,"Matroska"
+TINFO:0,36,0,"seamless"
+TINFO:0,49,0,"Director's cut"
+SINFO:0,0,1,6201,"Video"
+SINFO:0,0,12,0,"MVC"
+SINFO:0,0,41,0,"MpegH"
+SINFO:0,0,50,0,"4"
+SINFO:0,1,1,6202,"Audio"
+SINFO:0,1,40,0,"7.1"
+SINFO:0,1,41,0,"FLAC"
+SINFO:0,1,43,0,"48000"
+SINFO:0,1,44,0,"24"
+SINFO:0,1,45,0,"2"
+SINFO:0,1,46,0,"Stereo"
+SINFO:0,1,47,0,"3"
+SINFO:0,1,48,0,"Downmix"
+SINFO:0,2,1,6203,"Subtitles"
+SINFO:0,2,12,0,"forced"
+`))
+	assert.Nil(t, err)
+	title := result.Titles[0]
+	assert.Equal(t, "2", title.AngleInfo)
+	assert.Equal(t, 7, title.OriginalTitleId)
+	assert.Equal(t, 3, title.SegmentsCount)
+	assert.Equal(t, "TitleTree", title.TreeInfo)
+	assert.Equal(t, "TitlePanel", title.PanelTitle)
+	assert.Equal(t, 10, title.OrderWeight)
+	assert.Equal(t, "mkv", title.OutputFormat)
+	assert.Equal(t, "Matroska", title.OutputFormatDescription)
+	assert.Equal(t, "seamless", title.SeamlessInfo)
+	assert.Equal(t, "Director's cut", title.Comment)
+
+	video := title.VideoStreams[0]
+	assert.Equal(t, "MVC", video.StreamTypeExtension)
+	assert.Equal(t, "MpegH", video.OutputCodecShort)
+	assert.Equal(t, 4, video.OffsetSequenceId)
+
+	audio := title.AudioStreams[0]
+	assert.Equal(t, "7.1", audio.ChannelLayoutName)
+	assert.Equal(t, "FLAC", audio.OutputCodecShort)
+	assert.Equal(t, 48000, audio.OutputSampleRate)
+	assert.Equal(t, 24, audio.OutputSampleSize)
+	assert.Equal(t, 2, audio.OutputChannelCount)
+	assert.Equal(t, "Stereo", audio.OutputChannelLayoutName)
+	assert.Equal(t, 3, audio.OutputChannelLayout)
+	assert.Equal(t, "Downmix", audio.OutputMixDescription)
+
+	assert.Equal(t, "forced", title.SubtitleStreams[0].StreamTypeExtension)
+}
+
+func TestParseDiscInfoMetrics(t *testing.T) {
+	output := "TCOUNT:1\nTINFO:0,2,0,\"Title\"\nTINFO:5,2,0,\"Missing\"\nCINFO:bad\nNEW:1,2\n\ngarbage\n"
+	result, err := ParseDiscInfo(strings.NewReader(output))
+	assert.Nil(t, err)
+	assert.Equal(t, 7, result.Report.Lines)
+	assert.Equal(t, int64(len(output)), result.Report.Bytes)
+	assert.Equal(t, map[string]int{"NEW": 1}, result.Report.UnknownPrefixes)
+	assert.Equal(t, 3, result.Report.Malformed)
+}
+
+func TestParseDiscInfoBadTitleCount(t *testing.T) {
+	for _, count := range []string{"-1", "999999999"} {
+		result, err := ParseDiscInfo(strings.NewReader("TCOUNT:" + count + "\nTINFO:1,2,0,\"Second\"\nSINFO:1,0,1,6201,\"Video\"\nTINFO:0,2,0,\"First\"\n"))
+		assert.Nil(t, err, count)
+		assert.Equal(t, 1, result.Report.Malformed, count)
+		if assert.Len(t, result.Titles, 2, count) {
+			assert.Equal(t, 0, result.Titles[0].Id)
+			assert.Equal(t, "First", result.Titles[0].Name)
+			assert.Equal(t, 1, result.Titles[1].Id)
+			assert.Equal(t, "Second", result.Titles[1].Name)
+			assert.Len(t, result.Titles[1].VideoStreams, 1)
+		}
+	}
+}
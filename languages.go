@@ -0,0 +1,50 @@
+package makemkv
+
+import "strings"
+
+type LanguageReport struct {
+	TitleId          int
+	MissingAudio     []string
+	MissingSubtitles []string
+}
+
+func (r LanguageReport) Complete() bool {
+	return len(r.MissingAudio) == 0 && len(r.MissingSubtitles) == 0
+}
+
+// MissingLanguages reports, for every title on the disc, which of the given
+// language codes have no matching audio or subtitle stream.
+func MissingLanguages(disc DiscInfo, langs ...string) []LanguageReport {
+	reports := make([]LanguageReport, 0, len(disc.Titles))
+	for _, title := range disc.Titles {
+		report := LanguageReport{TitleId: title.Id}
+		for _, lang := range langs {
+			if !hasAudioLang(title, lang) {
+				report.MissingAudio = append(report.MissingAudio, lang)
+			}
+			if !hasSubtitleLang(title, lang) {
+				report.MissingSubtitles = append(report.MissingSubtitles, lang)
+			}
+		}
+		reports = append(reports, report)
+	}
+	return reports
+}
+
+func hasAudioLang(title TitleInfo, lang string) bool {
+	for _, stream := range title.AudioStreams {
+		if strings.EqualFold(stream.LangCode, lang) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasSubtitleLang(title TitleInfo, lang string) bool {
+	for _, stream := range title.SubtitleStreams {
+		if strings.EqualFold(stream.LangCode, lang) {
+			return true
+		}
+	}
+	return false
+}
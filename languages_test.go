@@ -0,0 +1,32 @@
+package makemkv
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMissingLanguages(t *testing.T) {
+	disc := DiscInfo{
+		Titles: []TitleInfo{
+			{
+				Id:              0,
+				AudioStreams:    []AudioStreamInfo{{LangCode: "eng"}, {LangCode: "fra"}},
+				SubtitleStreams: []SubtitleStreamInfo{{LangCode: "eng"}},
+			},
+			{
+				Id:           1,
+				AudioStreams: []AudioStreamInfo{{LangCode: "eng"}},
+			},
+		},
+	}
+	reports := MissingLanguages(disc, "eng", "fra")
+	assert.Equal(t, 2, len(reports))
+	assert.Equal(t, 0, reports[0].TitleId)
+	assert.Nil(t, reports[0].MissingAudio)
+	assert.Equal(t, []string{"fra"}, reports[0].MissingSubtitles)
+	assert.False(t, reports[0].Complete())
+	assert.Equal(t, 1, reports[1].TitleId)
+	assert.Equal(t, []string{"fra"}, reports[1].MissingAudio)
+	assert.Equal(t, []string{"eng", "fra"}, reports[1].MissingSubtitles)
+}
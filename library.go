@@ -0,0 +1,166 @@
+package makemkv
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+type LibraryOptions struct {
+	Mkv MkvOptions
+	// how many scans run at once, 1 when unset
+	Concurrency int
+	// a catalog from an earlier scan; entries for paths whose modification
+	// time and size haven't changed since are reused instead of scanned again
+	Previous Catalog
+}
+
+type CatalogEntry struct {
+	Disc *DiscInfo
+	// modification time and size of the ISO when it was scanned; for backups
+	// the time is the root's and the size is of everything under it
+	ModTime time.Time
+	Size    int64
+	Err     error
+}
+
+// unchanged is false for failed scans so that they are retried
+func (e CatalogEntry) unchanged(modTime time.Time, size int64) bool {
+	return e.Err == nil && e.ModTime.Equal(modTime) && e.Size == size
+}
+
+// Catalog holds scan results keyed by the path of the ISO or backup root
+type Catalog map[string]CatalogEntry
+
+// Paths returns the catalog's paths in sorted order
+func (c Catalog) Paths() []string {
+	paths := make([]string, 0, len(c))
+	for path := range c {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// ScanLibrary finds every ISO and backup folder under root and scans them,
+// backups with ScanBackup. A failed scan is recorded on its entry rather
+// than stopping the rest, and so is a directory that can't be read, under
+// its own path. The error returned is only for failing to walk root.
+func ScanLibrary(root string, opts LibraryOptions) (Catalog, error) {
+	type source struct {
+		path    string
+		backup  bool
+		modTime time.Time
+		size    int64
+	}
+	var sources []source
+	unreadable := make(map[string]error)
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == root {
+				return err
+			}
+			unreadable[path] = err
+			return fs.SkipDir
+		}
+		if d.IsDir() {
+			if isBackupFolder(d.Name()) {
+				dir := filepath.Dir(path)
+				info, err := os.Stat(dir)
+				if err != nil {
+					return nil
+				}
+				if size, _, err := treeSize(dir); err == nil {
+					sources = append(sources, source{dir, true, info.ModTime(), size})
+				}
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if strings.EqualFold(filepath.Ext(path), ".iso") {
+			if info, err := d.Info(); err == nil {
+				sources = append(sources, source{path, false, info.ModTime(), info.Size()})
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	concurrency := opts.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	catalog := make(Catalog, len(sources)+len(unreadable))
+	for path, err := range unreadable {
+		catalog[path] = CatalogEntry{Err: err}
+	}
+	// reused entries go in before any scan can write to catalog
+	var scans []source
+	for _, src := range sources {
+		if prev, ok := opts.Previous[src.path]; ok && prev.unchanged(src.modTime, src.size) {
+			catalog[src.path] = prev
+		} else {
+			scans = append(scans, src)
+		}
+	}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+	for _, src := range scans {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(src source) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			var disc *DiscInfo
+			var err error
+			if src.backup {
+				disc, err = ScanBackup(src.path, opts.Mkv)
+			} else {
+				disc, err = Info(NewIsoDevice(src.path), opts.Mkv).Run()
+			}
+			mu.Lock()
+			catalog[src.path] = CatalogEntry{Disc: disc, ModTime: src.modTime, Size: src.size, Err: err}
+			mu.Unlock()
+		}(src)
+	}
+	wg.Wait()
+	return catalog, nil
+}
+
+type CatalogChanges struct {
+	Added     []string
+	Modified  []string
+	Removed   []string
+	Unchanged []string
+}
+
+// Changes compares c, typically the result of ScanLibrary with Previous set,
+// against the previous catalog
+func (c Catalog) Changes(previous Catalog) CatalogChanges {
+	var changes CatalogChanges
+	for _, path := range c.Paths() {
+		entry := c[path]
+		if prev, ok := previous[path]; !ok {
+			changes.Added = append(changes.Added, path)
+		} else if prev.unchanged(entry.ModTime, entry.Size) {
+			changes.Unchanged = append(changes.Unchanged, path)
+		} else {
+			changes.Modified = append(changes.Modified, path)
+		}
+	}
+	for _, path := range previous.Paths() {
+		if _, ok := c[path]; !ok {
+			changes.Removed = append(changes.Removed, path)
+		}
+	}
+	return changes
+}
@@ -0,0 +1,117 @@
+package makemkv
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScanLibrary(t *testing.T) {
+	// no makemkvcon, so every scan that actually runs fails
+	t.Setenv("PATH", "")
+
+	root := t.TempDir()
+	iso := filepath.Join(root, "a", "Movie.ISO")
+	backup := filepath.Join(root, "b", "Show")
+	assert.Nil(t, os.MkdirAll(filepath.Dir(iso), 0o755))
+	assert.Nil(t, os.WriteFile(iso, nil, 0o644))
+	assert.Nil(t, os.MkdirAll(filepath.Join(backup, "VIDEO_TS"), 0o755))
+	assert.Nil(t, os.WriteFile(filepath.Join(root, "notes.txt"), nil, 0o644))
+
+	catalog, err := ScanLibrary(root, LibraryOptions{Concurrency: 2})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{iso, backup}, catalog.Paths())
+	assert.NotNil(t, catalog[iso].Err)
+	assert.NotNil(t, catalog[backup].Err)
+
+	isoInfo, err := os.Stat(iso)
+	assert.Nil(t, err)
+	cached := CatalogEntry{Disc: &DiscInfo{Name: "cached"}, ModTime: isoInfo.ModTime()}
+	catalog, err = ScanLibrary(root, LibraryOptions{Previous: Catalog{iso: cached}})
+	assert.Nil(t, err)
+	assert.Equal(t, cached, catalog[iso])
+	assert.NotNil(t, catalog[backup].Err)
+
+	// a size change is enough to scan again
+	cached.Size = 1
+	catalog, err = ScanLibrary(root, LibraryOptions{Previous: Catalog{iso: cached}})
+	assert.Nil(t, err)
+	assert.NotNil(t, catalog[iso].Err)
+}
+
+func TestCatalogChanges(t *testing.T) {
+	now := time.Now()
+	previous := Catalog{
+		"/lib/same.iso":    {ModTime: now, Size: 10},
+		"/lib/grown.iso":   {ModTime: now, Size: 10},
+		"/lib/failed.iso":  {ModTime: now, Size: 10, Err: assert.AnError},
+		"/lib/removed.iso": {ModTime: now, Size: 10},
+	}
+	current := Catalog{
+		"/lib/same.iso":   {ModTime: now, Size: 10},
+		"/lib/grown.iso":  {ModTime: now, Size: 20},
+		"/lib/failed.iso": {ModTime: now, Size: 10},
+		"/lib/new.iso":    {ModTime: now, Size: 10},
+	}
+	assert.Equal(t, CatalogChanges{
+		Added:     []string{"/lib/new.iso"},
+		Modified:  []string{"/lib/failed.iso", "/lib/grown.iso"},
+		Removed:   []string{"/lib/removed.iso"},
+		Unchanged: []string{"/lib/same.iso"},
+	}, current.Changes(previous))
+}
+
+func TestScanLibraryFake(t *testing.T) {
+	root := t.TempDir()
+	backup := filepath.Join(root, "Show")
+	assert.Nil(t, os.MkdirAll(filepath.Join(backup, "VIDEO_TS"), 0o755))
+	locked := filepath.Join(root, "locked")
+	assert.Nil(t, os.MkdirAll(filepath.Join(locked, "inner"), 0o755))
+	assert.Nil(t, os.Chmod(locked, 0))
+	defer os.Chmod(locked, 0o755)
+	_, readErr := os.ReadDir(locked)
+
+	catalog, err := ScanLibrary(root, LibraryOptions{Mkv: fakeMakemkvcon(t, input, 0)})
+	assert.Nil(t, err)
+	if assert.Nil(t, catalog[backup].Err) && assert.NotNil(t, catalog[backup].Disc.Backup) {
+		assert.Equal(t, BackupDvd, catalog[backup].Disc.Backup.Kind)
+	}
+	// root can read anything, so the directory is only unreadable for others
+	if readErr != nil {
+		assert.Equal(t, []string{backup, locked}, catalog.Paths())
+		assert.NotNil(t, catalog[locked].Err)
+	}
+}
+
+func TestScanLibraryReused(t *testing.T) {
+	root := t.TempDir()
+	previous := make(Catalog)
+	var fresh []string
+	for i := 0; i < 16; i++ {
+		path := filepath.Join(root, strconv.Itoa(i)+".iso")
+		assert.Nil(t, os.WriteFile(path, []byte("iso"), 0o644))
+		if i%2 == 0 {
+			info, err := os.Stat(path)
+			assert.Nil(t, err)
+			previous[path] = CatalogEntry{Disc: &DiscInfo{Name: "Reused"}, ModTime: info.ModTime(), Size: info.Size()}
+		} else {
+			fresh = append(fresh, path)
+		}
+	}
+
+	catalog, err := ScanLibrary(root, LibraryOptions{Mkv: fakeMakemkvcon(t, input, 0), Previous: previous, Concurrency: 4})
+	assert.Nil(t, err)
+	assert.Equal(t, 16, len(catalog))
+	for path, entry := range previous {
+		assert.Equal(t, entry, catalog[path])
+	}
+	for _, path := range fresh {
+		if assert.NotNil(t, catalog[path].Disc, path) {
+			assert.Equal(t, "DiscName", catalog[path].Disc.Name)
+		}
+	}
+}
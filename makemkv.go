@@ -1,38 +1,69 @@
 package makemkv
 
 import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
 	"strconv"
 )
 
+var ErrConflictingOptions = errors.New("makemkv: conflicting options")
+
 type Status struct {
-	Title   string
-	Channel string
-	Current int
-	Total   int
-	Max     int
+	Title   string `json:"title"`
+	Channel string `json:"channel"`
+	// the message codes of the PRGT and PRGC lines Title and Channel came from
+	TitleCode   int    `json:"title_code,omitempty"`
+	ChannelCode int    `json:"channel_code,omitempty"`
+	Current     int    `json:"current"`
+	Total       int    `json:"total"`
+	Max         int    `json:"max"`
+	Seq         uint64 `json:"seq"`
+	Raw         string `json:"raw,omitempty"`
+	// SavingTitle is the id of the title a rip is saving, empty until it is
+	// known. Jobs ripping one title know it from the start, rips of every
+	// title learn it from makemkvcon's per-title save messages.
+	SavingTitle string `json:"saving_title,omitempty"`
+	// Final is set on the last status of a job, which repeats the progress
+	// before it. Stopped says why the job was stopped, if it was.
+	Final   bool       `json:"final,omitempty"`
+	Stopped StopReason `json:"stopped,omitempty"`
 }
 
 type MkvOptions struct {
-	Messages  *string
-	Progress  *string
-	Debug     *string
+	Messages  Output
+	Progress  Output
+	Debug     Output
 	Directio  *bool
 	Cache     *int
 	Minlength *int
 	Noscan    bool
 	Decrypt   bool
+	Transport Transport
+	Audit     AuditLog
+	// a conversion profile passed with --profile, either a .mmcp.xml file or
+	// one built in Go and written out to a temporary file for the job
+	ProfileFile string
+	Profile     *Profile
+	// spins the drive up before the job and down once it has been idle
+	Power *DrivePower
+	// the makemkvcon to run, looked up on PATH when empty
+	Binary string
+	// added to the environment makemkvcon inherits
+	Env []string
 }
 
 func (m MkvOptions) toStrings() []string {
 	result := []string{"-r"}
-	if m.Messages != nil {
-		result = append(result, "--messages="+*m.Messages)
+	if !m.Messages.IsZero() {
+		result = append(result, "--messages="+m.Messages.String())
 	}
-	if m.Progress != nil {
-		result = append(result, "--progress="+*m.Progress)
+	if !m.Progress.IsZero() {
+		result = append(result, "--progress="+m.Progress.String())
 	}
-	if m.Debug != nil {
-		result = append(result, "--debug="+*m.Debug)
+	if !m.Debug.IsZero() {
+		result = append(result, "--debug="+m.Debug.String())
 	}
 	if m.Directio != nil {
 		result = append(result, "--directio="+strconv.FormatBool(*m.Directio))
@@ -49,13 +80,89 @@ func (m MkvOptions) toStrings() []string {
 	if m.Decrypt {
 		result = append(result, "--decrypt")
 	}
+	if m.ProfileFile != "" {
+		result = append(result, "--profile="+m.ProfileFile)
+	}
 	return result
 }
 
+// withProgress makes sure progress lines end up on stdout next to the robot
+// output when the job has someone to hand them to, rejecting explicit
+// settings that would send them elsewhere.
+func (m MkvOptions) withProgress(wanted bool) (MkvOptions, error) {
+	if m.Messages == OutputSame {
+		return m, fmt.Errorf("%w: --messages can't be -same", ErrConflictingOptions)
+	}
+	if !wanted {
+		return m, nil
+	}
+	if !m.Messages.IsZero() && m.Messages != OutputStdout && m.Transport != TransportFile {
+		return m, fmt.Errorf("%w: progress needs --messages=-stdout, got %q", ErrConflictingOptions, m.Messages)
+	}
+	if !m.Progress.IsZero() && m.Progress != OutputSame {
+		return m, fmt.Errorf("%w: progress needs --progress=-same, got %q", ErrConflictingOptions, m.Progress)
+	}
+	if m.Transport != TransportFile {
+		m.Messages = OutputStdout
+	}
+	m.Progress = OutputSame
+	return m, nil
+}
+
+// Deprecated: assign an Output to Messages.
+func (m *MkvOptions) SetMessages(messages string) {
+	m.Messages = outputOf(messages)
+}
+
+// Deprecated: assign an Output to Progress.
+func (m *MkvOptions) SetProgress(progress string) {
+	m.Progress = outputOf(progress)
+}
+
+// Deprecated: assign an Output to Debug.
+func (m *MkvOptions) SetDebug(debug string) {
+	m.Debug = outputOf(debug)
+}
+
+func (m *MkvOptions) SetDirectio(directio bool) {
+	m.Directio = &directio
+}
+
+func (m *MkvOptions) SetCache(cache int) {
+	m.Cache = &cache
+}
+
+func (m *MkvOptions) SetMinlength(minlength int) {
+	m.Minlength = &minlength
+}
+
+func Ptr[T any](v T) *T {
+	return &v
+}
+
+// Deprecated: use Ptr or the MkvOptions setters.
 func Stropt(s string) *string {
-	return &s
+	return Ptr(s)
 }
 
+// Deprecated: use Ptr or the MkvOptions setters.
 func Intopt(i int) *int {
-	return &i
+	return Ptr(i)
+}
+
+// parentEnv is set on every makemkvcon this package starts to the pid that
+// started it, so ReapOrphans can tell its own orphans from anybody else's
+// makemkvcon
+const parentEnv = "GO_MAKEMKV_PARENT"
+
+func newCommand(opts MkvOptions, args ...string) *exec.Cmd {
+	binary := opts.Binary
+	if binary == "" {
+		binary = "makemkvcon"
+	}
+	cmd := exec.Command(binary, append(opts.toStrings(), args...)...)
+	cmd.Env = append(os.Environ(), opts.Env...)
+	cmd.Env = append(cmd.Env, parentEnv+"="+strconv.Itoa(os.Getpid()))
+	setProcessGroup(cmd)
+	return cmd
 }
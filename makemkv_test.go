@@ -0,0 +1,56 @@
+package makemkv
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMkvOptionsToStrings(t *testing.T) {
+	var opts MkvOptions
+	assert.Equal(t, []string{"-r"}, opts.toStrings())
+
+	opts.SetCache(1024)
+	opts.SetDirectio(false)
+	opts.SetMinlength(120)
+	opts.Noscan = true
+	assert.Equal(t, []string{"-r", "--directio=false", "--cache=1024", "--minlength=120", "--noscan"}, opts.toStrings())
+
+	debug, err := OutputFile("/tmp/debug.log")
+	assert.Nil(t, err)
+	opts = MkvOptions{Messages: OutputNull, Debug: debug, Cache: Intopt(16)}
+	assert.Equal(t, []string{"-r", "--messages=-null", "--debug=/tmp/debug.log", "--cache=16"}, opts.toStrings())
+
+	opts = MkvOptions{}
+	opts.SetMessages("-stderr")
+	opts.SetProgress("-same")
+	opts.SetDebug("/tmp/debug.log")
+	assert.Equal(t, []string{"-r", "--messages=-stderr", "--progress=-same", "--debug=/tmp/debug.log"}, opts.toStrings())
+}
+
+func TestOutputFile(t *testing.T) {
+	for _, path := range []string{"", "-same", "-stdout"} {
+		_, err := OutputFile(path)
+		assert.ErrorIs(t, err, ErrInvalidOutput, path)
+	}
+	out, err := OutputFile("./-same")
+	assert.Nil(t, err)
+	assert.Equal(t, "./-same", out.String())
+}
+
+func TestMkvOptionsWithProgress(t *testing.T) {
+	opts, err := MkvOptions{}.withProgress(false)
+	assert.Nil(t, err)
+	assert.Equal(t, MkvOptions{}, opts)
+
+	opts, err = MkvOptions{}.withProgress(true)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"-r", "--messages=-stdout", "--progress=-same"}, opts.toStrings())
+
+	_, err = MkvOptions{Progress: OutputStdout}.withProgress(true)
+	assert.ErrorIs(t, err, ErrConflictingOptions)
+	_, err = MkvOptions{Messages: OutputNull}.withProgress(true)
+	assert.ErrorIs(t, err, ErrConflictingOptions)
+	_, err = MkvOptions{Messages: OutputSame}.withProgress(false)
+	assert.ErrorIs(t, err, ErrConflictingOptions)
+}
@@ -0,0 +1,142 @@
+// Package makemkvtest runs the test binary in place of makemkvcon, so jobs
+// can be tested end to end against canned robot output.
+//
+// Call Main first thing in TestMain, then run jobs with the Binary and Env of
+// a Fake:
+//
+//	func TestMain(m *testing.M) {
+//		makemkvtest.Main()
+//		os.Exit(m.Run())
+//	}
+//
+//	fake := makemkvtest.Output(t, output, 0)
+//	opts := makemkv.MkvOptions{Binary: fake.Binary, Env: fake.Env}
+package makemkvtest
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	outputEnv = "GO_MAKEMKV_FAKE_OUTPUT"
+	exitEnv   = "GO_MAKEMKV_FAKE_EXIT"
+	scriptEnv = "GO_MAKEMKV_FAKE_SCRIPT"
+	// how long to keep running after the output, for jobs to be stopped
+	holdEnv = "GO_MAKEMKV_FAKE_HOLD"
+)
+
+// Fake is what a job needs to run the fake, set them on MkvOptions
+type Fake struct {
+	Binary string
+	Env    []string
+}
+
+// Main replays the canned output and exits when the test binary was started
+// as a Fake, it returns straight away otherwise
+func Main() {
+	if file := os.Getenv(outputEnv); file != "" {
+		os.Exit(replay(file, os.Args[1:]))
+	}
+	if file := os.Getenv(scriptEnv); file != "" {
+		os.Exit(replayScript(file, os.Args[1:]))
+	}
+}
+
+// Output is a fake that prints output whatever it is asked and exits with
+// code
+func Output(t testing.TB, output string, code int) Fake {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), "output")
+	if err := os.WriteFile(file, []byte(output), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return Fake{
+		Binary: os.Args[0],
+		Env:    []string{outputEnv + "=" + file, exitEnv + "=" + strconv.Itoa(code)},
+	}
+}
+
+// Script is Output with the output depending on the command line. Keys are
+// matched against the leading arguments after the options, like
+// "mkv iso:/disc.iso 1", and the longest match wins. "" matches anything.
+func Script(t testing.TB, outputs map[string]string, code int) Fake {
+	t.Helper()
+	data, err := json.Marshal(outputs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(t.TempDir(), "script.json")
+	if err := os.WriteFile(file, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return Fake{
+		Binary: os.Args[0],
+		Env:    []string{scriptEnv + "=" + file, exitEnv + "=" + strconv.Itoa(code)},
+	}
+}
+
+// Hold is an Env entry that keeps the fake running for d after its output,
+// for testing jobs being stopped or cancelled
+func Hold(d time.Duration) string {
+	return holdEnv + "=" + d.String()
+}
+
+func replay(file string, args []string) int {
+	output, err := os.ReadFile(file)
+	if err != nil {
+		return 2
+	}
+	return write(output, args)
+}
+
+func replayScript(file string, args []string) int {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return 2
+	}
+	var outputs map[string]string
+	if err := json.Unmarshal(data, &outputs); err != nil {
+		return 2
+	}
+	var positional []string
+	for _, arg := range args {
+		if !strings.HasPrefix(arg, "-") {
+			positional = append(positional, arg)
+		}
+	}
+	line := strings.Join(positional, " ")
+	best, found := "", false
+	for key := range outputs {
+		if (line == key || strings.HasPrefix(line, key+" ") || key == "") && (!found || len(key) > len(best)) {
+			best, found = key, true
+		}
+	}
+	if !found {
+		return 2
+	}
+	return write([]byte(outputs[best]), args)
+}
+
+func write(output []byte, args []string) int {
+	var err error
+	out := os.Stdout
+	for _, arg := range args {
+		if path, ok := strings.CutPrefix(arg, "--messages="); ok && path != "-stdout" {
+			if out, err = os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0); err != nil {
+				return 2
+			}
+		}
+	}
+	out.Write(output)
+	if hold, err := time.ParseDuration(os.Getenv(holdEnv)); err == nil {
+		time.Sleep(hold)
+	}
+	code, _ := strconv.Atoi(os.Getenv(exitEnv))
+	return code
+}
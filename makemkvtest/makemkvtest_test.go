@@ -0,0 +1,47 @@
+package makemkvtest
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMain(m *testing.M) {
+	Main()
+	os.Exit(m.Run())
+}
+
+func run(fake Fake, args ...string) (string, int) {
+	cmd := exec.Command(fake.Binary, args...)
+	cmd.Env = append(os.Environ(), fake.Env...)
+	out, err := cmd.Output()
+	var exit *exec.ExitError
+	if errors.As(err, &exit) {
+		return string(out), exit.ExitCode()
+	}
+	return string(out), 0
+}
+
+func TestOutput(t *testing.T) {
+	out, code := run(Output(t, "TCOUNT:0\n", 3), "-r", "info", "disc:0")
+	assert.Equal(t, "TCOUNT:0\n", out)
+	assert.Equal(t, 3, code)
+}
+
+func TestScript(t *testing.T) {
+	fake := Script(t, map[string]string{
+		"":                  "DRV:0\n",
+		"info iso:/a.iso":   "TCOUNT:1\n",
+		"mkv iso:/a.iso 0":  "PRGV:0,0,1\n",
+		"mkv iso:/a.iso 01": "wrong\n",
+	}, 0)
+	out, _ := run(fake, "-r", "info", "iso:/a.iso")
+	assert.Equal(t, "TCOUNT:1\n", out)
+	out, _ = run(fake, "-r", "--noscan", "mkv", "iso:/a.iso", "0", "/out")
+	assert.Equal(t, "PRGV:0,0,1\n", out)
+	out, _ = run(fake, "-r", "info", "disc:9999")
+	assert.Equal(t, "DRV:0\n", out)
+}
@@ -0,0 +1,62 @@
+package makemkv
+
+import (
+	"io/fs"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// HashCheck is one file of a backup and how many hash check failures
+// makemkvcon reported for it. makemkvcon only checks files the disc carries
+// hashes for and says nothing about the ones that pass, so a zero count
+// means no failure was reported rather than a verified file.
+type HashCheck struct {
+	// slash separated and relative to the backup folder, like BDMV/STREAM/00000.m2ts
+	File     string
+	Failures int
+}
+
+func (h HashCheck) Ok() bool {
+	return h.Failures == 0
+}
+
+// hashManifest lists the files under dir along with the failures reported
+// for them. Files named in failures that aren't on disk are listed too.
+func hashManifest(dir string, failures map[string]int) []HashCheck {
+	counts := make(map[string]int, len(failures))
+	for file, n := range failures {
+		counts[manifestPath(file)] += n
+	}
+	var checks []HashCheck
+	seen := make(map[string]bool)
+	filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return nil
+		}
+		file := manifestPath(filepath.ToSlash(rel))
+		seen[file] = true
+		checks = append(checks, HashCheck{File: file, Failures: counts[file]})
+		return nil
+	})
+	for file, n := range counts {
+		if !seen[file] {
+			checks = append(checks, HashCheck{File: file, Failures: n})
+		}
+	}
+	sort.Slice(checks, func(a, b int) bool {
+		return checks[a].File < checks[b].File
+	})
+	return checks
+}
+
+// manifestPath normalizes the disc paths makemkvcon prints, which start at
+// the disc root, to match paths relative to the backup folder
+func manifestPath(file string) string {
+	return strings.TrimPrefix(path.Clean("/"+strings.ReplaceAll(file, "\\", "/")), "/")
+}
@@ -0,0 +1,34 @@
+package makemkv
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHashManifest(t *testing.T) {
+	dir := t.TempDir()
+	stream := filepath.Join(dir, "BDMV", "STREAM")
+	assert.Nil(t, os.MkdirAll(stream, 0o755))
+	for _, name := range []string{"00000.m2ts", "00001.m2ts"} {
+		assert.Nil(t, os.WriteFile(filepath.Join(stream, name), nil, 0o644))
+	}
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "BDMV", "index.bdmv"), nil, 0o644))
+
+	var summary ripSummary
+	for _, file := range []string{"/BDMV/STREAM/00001.m2ts", "/BDMV/STREAM/00001.m2ts", "/BDMV/STREAM/00009.m2ts"} {
+		summary.observe(Message{Format: "Hash check failed for file %1 at offset %2, file is corrupt", Params: []string{file, "0"}})
+	}
+
+	checks := hashManifest(dir, summary.hashFailures)
+	assert.Equal(t, []HashCheck{
+		{File: "BDMV/STREAM/00000.m2ts"},
+		{File: "BDMV/STREAM/00001.m2ts", Failures: 2},
+		{File: "BDMV/STREAM/00009.m2ts", Failures: 1},
+		{File: "BDMV/index.bdmv"},
+	}, checks)
+	assert.True(t, checks[0].Ok())
+	assert.False(t, checks[1].Ok())
+}
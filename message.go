@@ -0,0 +1,93 @@
+package makemkv
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Message is a parsed MSG line:
+// MSG:code,flags,count,"message","format","param0","param1",...
+type Message struct {
+	Code  int
+	Flags int
+	// the message as makemkvcon formatted it
+	Text string
+	// the untranslated format string, with %1, %2... standing for Params
+	Format string
+	Params []string
+	// the message's place among the events of a job, counted together with
+	// Status.Seq; only set on messages sent on a Messagechan
+	Seq uint64
+	// set on the updates a Messagechan gets about a message makemkvcon keeps
+	// printing to how often it has been printed so far, the message being
+	// the latest occurrence. Zero on messages passed on as they come.
+	Count int
+}
+
+func parseMessage(content string) (Message, bool) {
+	fields := splitQuoted(content)
+	if len(fields) < 4 {
+		return Message{}, false
+	}
+	var msg Message
+	var err error
+	if msg.Code, err = strconv.Atoi(fields[0]); err != nil {
+		return Message{}, false
+	}
+	msg.Flags, _ = strconv.Atoi(fields[1])
+	msg.Text = fields[3]
+	if len(fields) > 4 {
+		msg.Format = fields[4]
+	}
+	if len(fields) > 5 {
+		msg.Params = fields[5:]
+	}
+	return msg, true
+}
+
+func (m Message) Param(i int) string {
+	if i < len(m.Params) {
+		return m.Params[i]
+	}
+	return ""
+}
+
+// splitQuoted splits robot output on commas outside of double quotes and
+// unquotes the quoted fields. a quote only closes a field when followed by a
+// comma or the end of the line, so stray quotes inside messages survive.
+func splitQuoted(s string) []string {
+	var fields []string
+	for len(s) > 0 {
+		var field string
+		var more bool
+		if s[0] == '"' {
+			field, s, more = cutQuoted(s[1:])
+		} else {
+			field, s, more = strings.Cut(s, ",")
+		}
+		fields = append(fields, field)
+		if !more {
+			return fields
+		}
+	}
+	return fields
+}
+
+// cutQuoted reads a quoted field starting after its opening quote, returning
+// the rest of the line after the following comma if there is one. makemkvcon
+// escapes quotes and backslashes inside strings with a backslash.
+func cutQuoted(s string) (field string, rest string, more bool) {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '\\' && i+1 < len(s) && (s[i+1] == '"' || s[i+1] == '\\'):
+			i++
+		case c == '"' && i == len(s)-1:
+			return b.String(), "", false
+		case c == '"' && s[i+1] == ',':
+			return b.String(), s[i+2:], true
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String(), "", false
+}
@@ -0,0 +1,32 @@
+package makemkv
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitQuoted(t *testing.T) {
+	assert.Equal(t, []string{"5011", "0", "0", "Operation successfully completed", "Operation successfully completed"},
+		splitQuoted(`5011,0,0,"Operation successfully completed","Operation successfully completed"`))
+	assert.Equal(t, []string{"1", "a, b", "c"}, splitQuoted(`1,"a, b","c"`))
+	assert.Equal(t, []string{"1", `say "hi"`, ""}, splitQuoted(`1,"say "hi"",""`))
+	assert.Equal(t, []string{"1", `say "hi", bye`, `C:\dir\`, `C:\Users`}, splitQuoted(`1,"say \"hi\", bye","C:\\dir\\","C:\Users"`))
+	assert.Equal(t, []string{"1", "open"}, splitQuoted(`1,"open`))
+}
+
+func TestParseMessage(t *testing.T) {
+	msg, ok := parseMessage(`3025,16777216,3,"Title #00003.mpls has length of 8 seconds","Title #%1 has length of %2 seconds","00003.mpls","8"`)
+	assert.True(t, ok)
+	assert.Equal(t, Message{
+		Code:   3025,
+		Flags:  16777216,
+		Text:   "Title #00003.mpls has length of 8 seconds",
+		Format: "Title #%1 has length of %2 seconds",
+		Params: []string{"00003.mpls", "8"},
+	}, msg)
+	assert.Equal(t, "", msg.Param(2))
+
+	_, ok = parseMessage(`garbage`)
+	assert.False(t, ok)
+}
@@ -1,18 +1,87 @@
 package makemkv
 
 import (
-	"bufio"
-	"os/exec"
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"sort"
 	"strconv"
-	"strings"
+	"time"
 )
 
 type MkvJob struct {
-	Statuschan  chan Status
-	device      Device
-	titleId     string
+	// Statuschan receives statuses in the order makemkvcon printed them, all
+	// sent from the goroutine calling RunContext, with strictly increasing
+	// Seq even across a retried rip or the titles of MkvTitles. The last one
+	// is always a Final status, sent once makemkvcon has exited and never
+	// dropped. Every send completes before RunContext returns, so the
+	// returned result or error is always the last thing a caller observes.
+	// Delivery decides what happens when the consumer falls behind.
+	Statuschan chan Status
+	// Messagechan receives MSG lines as they are parsed, from the same
+	// goroutine. A message makemkvcon keeps printing, read retries and the
+	// like, comes once and is then updated with its Count every few seconds
+	// and when the job ends. Messages are numbered on the same count as statuses, so
+	// sorting what both channels delivered by Seq restores the order
+	// makemkvcon printed it in. Sends always block.
+	Messagechan chan Message
+	Delivery    DeliveryPolicy
+	IncludeRaw  bool
+	// Expect is the title titleId was picked from. When set and makemkvcon
+	// finishes without saving or failing any title, as it does for an id
+	// the disc no longer has, the disc is scanned again and the rip retried
+	// once against whichever title now matches it, as ids can shift between
+	// scans. The job itself keeps its title id.
+	Expect *TitleInfo
+	// Scanned is an earlier Info of the device, used to tell which title each
+	// saved file came from. Since the device is already known the rip passes
+	// --noscan, which only keeps makemkvcon from probing the other drives.
+	// Scanned is ignored when the DRV line the rip prints shows a different
+	// disc in the drive.
+	Scanned    *DiscInfo
+	Thresholds Thresholds
+	// Logger gets a line every LogStep percent of overall progress, 5 when
+	// LogStep is unset
+	Logger  *slog.Logger
+	LogStep int
+	// StartTimeout stops the job with StopStalled and a StartTimeoutError
+	// when makemkvcon prints nothing but its banner for this long, zero for
+	// no limit
+	StartTimeout time.Duration
+	device       Device
+	titleId      string
+	// set by MkvTitles, which rips them one at a time
+	titleIds    []int
 	destination string
 	options     MkvOptions
+	stopper     stopper
+}
+
+type RipResult struct {
+	WallTime   time.Duration
+	UserTime   time.Duration
+	SystemTime time.Duration
+	MaxRSS     int64 // bytes
+
+	DroppedStatuses uint64
+	Version         Version
+
+	Outcome Outcome
+	Saved   int
+	Failed  int
+	// mkv files found in the destination that were written during the job
+	Files []string
+	// each saved file and failed title, ordered by title id
+	Titles []TitleResult
+	// set when makemkvcon's summary disagrees with the saved Titles
+	SummaryMismatch bool
+	// messages printed more than once, each with how often it appeared
+	RepeatedMessages []MessageCount
+	// set by backup jobs asked for a manifest, see BackupJob.Manifest
+	HashChecks []HashCheck
+	// how much output there was and what the parser made of it
+	Report ParseReport
 }
 
 func Mkv(device Device, titleId int, destination string, opts MkvOptions) *MkvJob {
@@ -25,7 +94,7 @@ func Mkv(device Device, titleId int, destination string, opts MkvOptions) *MkvJo
 	}
 }
 
-func MkvAll(device Device, titleId int, destination string, opts MkvOptions) *MkvJob {
+func MkvAll(device Device, destination string, opts MkvOptions) *MkvJob {
 	return &MkvJob{
 		Statuschan:  nil,
 		device:      device,
@@ -35,60 +104,192 @@ func MkvAll(device Device, titleId int, destination string, opts MkvOptions) *Mk
 	}
 }
 
+// MkvTitles rips each of titleIds in turn, running makemkvcon once per
+// title, with one result covering them all. Expect and the retry it allows
+// only apply to jobs ripping a single title.
+func MkvTitles(device Device, titleIds []int, destination string, opts MkvOptions) *MkvJob {
+	return &MkvJob{
+		Statuschan:  nil,
+		device:      device,
+		titleIds:    slices.Clone(titleIds),
+		destination: destination,
+		options:     opts,
+	}
+}
+
+// Deprecated: use RunContext, which also returns the RipResult.
 func (j *MkvJob) Run() error {
-	dev := j.device.Type() + ":" + j.device.Device()
-	options := append(j.options.toStrings(), []string{"mkv", dev, j.titleId, j.destination}...)
-	cmd := exec.Command("makemkvcon", options...)
+	_, err := j.RunContext(context.Background())
+	return err
+}
 
-	var scanner bufio.Scanner
-	if out, err := cmd.StdoutPipe(); err != nil {
-		return err
+// RunContext runs the rip, stopping it when ctx is done. The error is then a
+// StoppedError wrapping ctx.Err(), with StopTimeout as the reason when the
+// deadline passed and StopCanceled otherwise.
+func (j *MkvJob) RunContext(ctx context.Context) (*RipResult, error) {
+	defer j.stopper.watch(ctx)()
+	if err := CheckAccess(j.device); err != nil {
+		return nil, err
+	}
+	j.options.Power.begin(j.device, j.options)
+	defer j.options.Power.end(j.device)
+	var result *RipResult
+	var summary ripSummary
+	var err error
+	if j.titleIds != nil {
+		result, summary, err = j.runTitles()
 	} else {
-		scanner = *bufio.NewScanner(out)
+		result, summary, err = j.run(j.titleId, j.Scanned, 0, nil)
+		if retry, disc := j.retryTitle(ctx, result, summary, err); retry != "" {
+			result, summary, err = j.run(retry, disc, summary.final.Seq, nil)
+		}
 	}
-	if err := cmd.Start(); err != nil {
-		return err
+	if result != nil && j.Statuschan != nil {
+		result.DroppedStatuses += sendFinal(j.Statuschan, j.Delivery, summary.final)
 	}
+	return result, err
+}
 
-	var title string
-	var channel string
-	var total int
-	var current int
-	var max int
+// retryTitle returns the title to rip again along with the scan it was
+// found in, or "" when the rip shouldn't be retried
+func (j *MkvJob) retryTitle(ctx context.Context, result *RipResult, summary ripSummary, err error) (string, *DiscInfo) {
+	if j.Expect == nil || j.titleId == "all" || result == nil || !summary.titleMissing() {
+		return "", nil
+	}
+	var stopped *StoppedError
+	if errors.As(err, &stopped) {
+		return "", nil
+	}
+	disc, scanErr := Info(j.device, MkvOptions{Audit: j.options.Audit, Binary: j.options.Binary, Env: j.options.Env}).RunContext(ctx)
+	if scanErr != nil {
+		return "", nil
+	}
+	id, ok := FindTitle(disc, j.Expect)
+	if !ok || strconv.Itoa(id) == j.titleId {
+		return "", nil
+	}
+	return strconv.Itoa(id), disc
+}
 
-	for scanner.Scan() {
-		line := scanner.Text()
-		prefix, content, found := strings.Cut(line, ":")
-		if !found {
-			continue
+// runTitles rips the titles of a MkvTitles job one after the other, carrying
+// on past failed titles but not past a stopped rip
+func (j *MkvJob) runTitles() (*RipResult, ripSummary, error) {
+	merged := &RipResult{}
+	var total ripSummary
+	var errs []error
+	// files written by earlier titles, which later ones would pick up again
+	earlier := make(map[string]bool)
+	for _, id := range j.titleIds {
+		result, summary, err := j.run(strconv.Itoa(id), j.Scanned, total.final.Seq, earlier)
+		if result == nil {
+			return nil, summary, err
 		}
-
-		parts := strings.Split(content, ",")
-		switch prefix {
-		case "PRGT":
-			title = parts[2]
-		case "PRGC":
-			channel = parts[2]
-		case "PRGV":
-			current, _ = strconv.Atoi(parts[0])
-			total, _ = strconv.Atoi(parts[1])
-			max, _ = strconv.Atoi(parts[2])
-			if j.Statuschan != nil {
-				select {
-				case j.Statuschan <- Status{
-					Title:   title,
-					Channel: channel,
-					Current: current,
-					Total:   total,
-					Max:     max,
-				}:
-				}
-			}
+		merged.add(result)
+		total.add(summary)
+		for _, file := range result.Files {
+			earlier[file] = true
+		}
+		// partial rips are summed up by the counts, fatal ones keep their error
+		if result.Outcome == OutcomeFatal && err != nil {
+			errs = append(errs, err)
+		}
+		var stopped *StoppedError
+		if errors.As(err, &stopped) {
+			break
 		}
 	}
+	sort.SliceStable(merged.Titles, func(i, k int) bool {
+		return merged.Titles[i].TitleId < merged.Titles[k].TitleId
+	})
+	var err error
+	merged.Outcome, err = total.outcome(errors.Join(errs...))
+	merged.Saved, merged.Failed = total.saved, total.failedCount()
+	return merged, total, err
+}
 
-	if err := cmd.Wait(); err != nil {
-		return err
+// add merges in the result of another run of makemkvcon for the same job
+func (r *RipResult) add(o *RipResult) {
+	r.WallTime += o.WallTime
+	r.UserTime += o.UserTime
+	r.SystemTime += o.SystemTime
+	r.MaxRSS = max(r.MaxRSS, o.MaxRSS)
+	r.DroppedStatuses += o.DroppedStatuses
+	if !o.Version.IsZero() {
+		r.Version = o.Version
+	}
+	r.Files = append(r.Files, o.Files...)
+	r.Titles = append(r.Titles, o.Titles...)
+	r.SummaryMismatch = r.SummaryMismatch || o.SummaryMismatch
+	r.RepeatedMessages = append(r.RepeatedMessages, o.RepeatedMessages...)
+	r.Report.add(o.Report)
+}
+
+// run rips titleId, with scanned as the Info it was picked from, seq the
+// last Seq already sent and earlier the files an earlier run of the job
+// saved, which are left out of Files
+func (j *MkvJob) run(titleId string, scanned *DiscInfo, seq uint64, earlier map[string]bool) (*RipResult, ripSummary, error) {
+	dev := j.device.Type() + ":" + j.device.Device()
+	opts := j.options
+	if scanned != nil && scanned.Device == dev {
+		opts.Noscan = true
+	}
+	opts, err := opts.withProgress(j.Statuschan != nil || j.Logger != nil)
+	if err != nil {
+		return nil, ripSummary{}, err
+	}
+	opts, file, cleanup, err := opts.withTransport()
+	if err != nil {
+		return nil, ripSummary{}, err
 	}
-	return nil
+	defer cleanup()
+	cmd := newCommand(opts, "mkv", dev, titleId, j.destination)
+	saving := titleId
+	if titleId == "all" {
+		saving = ""
+	}
+
+	start := time.Now()
+	result, summary, err := runWithProgress(cmd, file, opts.Audit, progress{
+		ch:           j.Statuschan,
+		messages:     j.Messagechan,
+		delivery:     j.Delivery,
+		includeRaw:   j.IncludeRaw,
+		thresholds:   j.Thresholds,
+		stopper:      &j.stopper,
+		logger:       j.Logger,
+		logStep:      j.LogStep,
+		startTimeout: j.StartTimeout,
+		seq:          seq,
+		saving:       saving,
+	})
+	// mtimes can be coarser than the clock, so allow for a little slack
+	for _, file := range savedFiles(j.destination, start.Add(-2*time.Second)) {
+		if !earlier[file] {
+			result.Files = append(result.Files, file)
+		}
+	}
+	wanted := -1
+	if titleId != "all" {
+		wanted, _ = strconv.Atoi(titleId)
+	}
+	if scanned != nil && !sameDisc(j.device, scanned, summary.drives) {
+		scanned = nil
+	}
+	result.Titles = titleResults(result.Files, summary.failedFiles, scanned, wanted)
+	// Files also holds partial files of failed titles, so count saved ones
+	saved := 0
+	for _, title := range result.Titles {
+		if title.Saved {
+			saved++
+		}
+	}
+	result.SummaryMismatch = summary.seen && summary.saved != saved
+	return result, summary, err
+}
+
+// Stop kills the running makemkvcon, making the rip return a StoppedError
+// for reason. With nothing running, the job's next run is stopped as it
+// starts.
+func (j *MkvJob) Stop(reason StopReason) {
+	j.stopper.stop(reason)
 }
@@ -0,0 +1,32 @@
+package makemkv
+
+// sameDisc reports whether scanned still describes what is in device, going
+// by the DRV lines a job printed. Images only have to be the one scanned,
+// drives also have to hold a disc with the same volume name.
+func sameDisc(device Device, scanned *DiscInfo, drives []DriveInfo) bool {
+	if scanned.Device != device.Type()+":"+device.Device() {
+		return false
+	}
+	if !DeviceCapabilities(device).Eject {
+		return true
+	}
+	if scanned.VolumeName == "" {
+		return false
+	}
+	for _, drive := range drives {
+		switch d := device.(type) {
+		case *DiscDevice:
+			if drive.Index != d.id {
+				continue
+			}
+		case *DevDevice:
+			if drive.DevicePath != d.Device() {
+				continue
+			}
+		default:
+			continue
+		}
+		return drive.HasDisc() && drive.DiscName == scanned.VolumeName
+	}
+	return false
+}
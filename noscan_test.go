@@ -0,0 +1,44 @@
+package makemkv
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSameDisc(t *testing.T) {
+	iso := NewIsoDevice("/disc.iso")
+	assert.True(t, sameDisc(iso, &DiscInfo{Device: "iso:/disc.iso"}, nil))
+	assert.False(t, sameDisc(iso, &DiscInfo{Device: "iso:/other.iso"}, nil))
+
+	drives, err := parseDrives(bufio.NewScanner(strings.NewReader(`DRV:0,2,999,1,"BD-RE HL-DT-ST BD-RE  WH16NS60 1.02","MOVIE","/dev/sr0"
+DRV:1,0,999,1,"DVD-RW","","/dev/sr1"
+`)))
+	assert.Nil(t, err)
+	assert.True(t, sameDisc(NewDiscDevice(0), &DiscInfo{Device: "disc:0", VolumeName: "MOVIE"}, drives))
+	assert.True(t, sameDisc(NewDevDevice("sr0"), &DiscInfo{Device: "dev:/dev/sr0", VolumeName: "MOVIE"}, drives))
+	assert.False(t, sameDisc(NewDiscDevice(0), &DiscInfo{Device: "disc:0", VolumeName: "SEQUEL"}, drives))
+	assert.False(t, sameDisc(NewDiscDevice(1), &DiscInfo{Device: "disc:1", VolumeName: "MOVIE"}, drives))
+	assert.False(t, sameDisc(NewDiscDevice(0), &DiscInfo{Device: "disc:0"}, drives))
+	assert.False(t, sameDisc(NewDiscDevice(0), &DiscInfo{Device: "disc:0", VolumeName: "MOVIE"}, nil))
+}
+
+func TestMkvScanned(t *testing.T) {
+	var audit bytes.Buffer
+	opts := fakeMakemkvcon(t, input, 0)
+	opts.Audit = NewAuditWriter(&audit)
+	disc, err := Info(NewIsoDevice("/disc.iso"), opts).Run()
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, "iso:/disc.iso", disc.Device)
+
+	job := Mkv(NewIsoDevice("/disc.iso"), 0, t.TempDir(), opts)
+	job.Scanned = disc
+	job.RunContext(context.Background())
+	assert.Contains(t, audit.String(), `"--noscan","mkv"`)
+}
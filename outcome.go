@@ -0,0 +1,172 @@
+package makemkv
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type Outcome int
+
+const (
+	OutcomeSuccess Outcome = iota
+	// some titles were saved and some were not
+	OutcomePartial
+	// nothing was saved
+	OutcomeFatal
+)
+
+func (o Outcome) String() string {
+	switch o {
+	case OutcomeSuccess:
+		return "success"
+	case OutcomePartial:
+		return "partial"
+	case OutcomeFatal:
+		return "fatal"
+	default:
+		return "unknown"
+	}
+}
+
+var ErrPartialSuccess = errors.New("makemkv: not all titles were saved")
+
+type PartialSuccessError struct {
+	Saved  int
+	Failed int
+	// ids of the titles makemkvcon reported as failing, when it said which
+	Titles []int
+	// whatever the process exited with, if it didn't exit cleanly
+	Err error
+}
+
+func (e *PartialSuccessError) Error() string {
+	s := fmt.Sprintf("makemkv: %d titles saved, %d failed", e.Saved, e.Failed)
+	if len(e.Titles) > 0 {
+		ids := make([]string, len(e.Titles))
+		for i, id := range e.Titles {
+			ids[i] = strconv.Itoa(id)
+		}
+		s += " (titles " + strings.Join(ids, ", ") + ")"
+	}
+	return s
+}
+
+func (e *PartialSuccessError) Is(target error) bool {
+	return target == ErrPartialSuccess || target == ErrTitleFailed
+}
+
+func (e *PartialSuccessError) Unwrap() error {
+	return e.Err
+}
+
+// ripSummary collects what makemkvcon had to say about saved titles
+type ripSummary struct {
+	seen         bool
+	saved        int
+	failed       int
+	failedTitles []int
+	// the file each failed title was being saved to
+	failedFiles map[int]string
+	// hash check failures by file, as named in the messages
+	hashFailures map[string]int
+	// the drives makemkvcon listed before starting
+	drives []DriveInfo
+	// the status to end the job with, numbered after everything sent
+	final Status
+}
+
+// add merges in the summary of another run of makemkvcon for the same job,
+// which came after everything in s
+func (s *ripSummary) add(o ripSummary) {
+	s.seen = s.seen || o.seen
+	s.saved += o.saved
+	s.failed += o.failedCount()
+	s.failedTitles = append(s.failedTitles, o.failedTitles...)
+	for id, file := range o.failedFiles {
+		if s.failedFiles == nil {
+			s.failedFiles = make(map[int]string)
+		}
+		s.failedFiles[id] = file
+	}
+	for file, n := range o.hashFailures {
+		if s.hashFailures == nil {
+			s.hashFailures = make(map[string]int)
+		}
+		s.hashFailures[file] += n
+	}
+	s.drives = o.drives
+	s.final = o.final
+}
+
+// titleMissing reports whether makemkvcon finished copying without saving or
+// failing anything, which is what a title id naming no title leads to
+func (s *ripSummary) titleMissing() bool {
+	return s.seen && s.saved == 0 && s.failedCount() == 0 && len(s.hashFailures) == 0
+}
+
+func (s *ripSummary) observe(msg Message) {
+	switch {
+	// "Copy complete. %1 titles saved." or "Copy complete. %1 titles saved, %2 failed."
+	case strings.HasPrefix(msg.Format, "Copy complete."):
+		s.seen = true
+		s.saved, _ = strconv.Atoi(msg.Param(0))
+		s.failed, _ = strconv.Atoi(msg.Param(1))
+	// "Hash check failed for file %1 at offset %2, file is corrupt"
+	case strings.HasPrefix(msg.Format, "Hash check failed"):
+		if s.hashFailures == nil {
+			s.hashFailures = make(map[string]int)
+		}
+		s.hashFailures[formatParam(msg, "file ")]++
+	// "Failed to save title %1 to file %2"
+	case strings.HasPrefix(msg.Format, "Failed to save title"):
+		if id, err := strconv.Atoi(msg.Param(0)); err == nil {
+			s.failedTitles = append(s.failedTitles, id)
+			if s.failedFiles == nil {
+				s.failedFiles = make(map[int]string)
+			}
+			s.failedFiles[id] = msg.Param(1)
+		}
+	}
+}
+
+// savingTitle returns the title id of a message makemkvcon prints as it
+// starts saving a title, like "Saving title %1 into file %2"
+func savingTitle(msg Message) (string, bool) {
+	if !strings.HasPrefix(msg.Format, "Saving title %1") {
+		return "", false
+	}
+	id := msg.Param(0)
+	if _, err := strconv.Atoi(id); err != nil {
+		return "", false
+	}
+	return id, true
+}
+
+func (s *ripSummary) failedCount() int {
+	if s.failed < len(s.failedTitles) {
+		return len(s.failedTitles)
+	}
+	return s.failed
+}
+
+// outcome combines the summary with how the process exited. A summary
+// reporting saved titles beats a non-zero exit status, which makemkvcon also
+// uses when only some titles failed.
+func (s *ripSummary) outcome(err error) (Outcome, error) {
+	failed := s.failedCount()
+	var stopped *StoppedError
+	switch {
+	case errors.As(err, &stopped):
+		return OutcomeFatal, err
+	case s.seen && s.saved > 0 && failed > 0, err != nil && s.saved > 0:
+		return OutcomePartial, &PartialSuccessError{Saved: s.saved, Failed: failed, Titles: s.failedTitles, Err: err}
+	case err != nil:
+		return OutcomeFatal, err
+	case s.seen && s.saved == 0 && failed > 0:
+		return OutcomeFatal, fmt.Errorf("makemkv: no titles saved, %d failed", failed)
+	default:
+		return OutcomeSuccess, nil
+	}
+}
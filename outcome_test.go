@@ -0,0 +1,49 @@
+package makemkv
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func summaryOf(t *testing.T, lines ...string) *ripSummary {
+	var summary ripSummary
+	for _, line := range lines {
+		msg, ok := parseMessage(line)
+		assert.True(t, ok, line)
+		summary.observe(msg)
+	}
+	return &summary
+}
+
+func TestRipSummaryOutcome(t *testing.T) {
+	summary := summaryOf(t, `5036,260,1,"Copy complete. 2 titles saved.","Copy complete. %1 titles saved.","2"`)
+	outcome, err := summary.outcome(nil)
+	assert.Equal(t, OutcomeSuccess, outcome)
+	assert.Nil(t, err)
+
+	summary = summaryOf(t,
+		`5003,0,2,"Failed to save title 1 to file /out/title_t01.mkv","Failed to save title %1 to file %2","1","/out/title_t01.mkv"`,
+		`5037,260,2,"Copy complete. 1 titles saved, 1 failed.","Copy complete. %1 titles saved, %2 failed.","1","1"`,
+	)
+	exit := errors.New("exit status 1")
+	outcome, err = summary.outcome(exit)
+	assert.Equal(t, OutcomePartial, outcome)
+	assert.ErrorIs(t, err, ErrPartialSuccess)
+	assert.ErrorIs(t, err, exit)
+	var partial *PartialSuccessError
+	if assert.ErrorAs(t, err, &partial) {
+		assert.Equal(t, []int{1}, partial.Titles)
+		assert.Equal(t, "makemkv: 1 titles saved, 1 failed (titles 1)", partial.Error())
+	}
+
+	outcome, err = (&ripSummary{}).outcome(exit)
+	assert.Equal(t, OutcomeFatal, outcome)
+	assert.Equal(t, exit, err)
+
+	stopped := &StoppedError{Reason: StopCanceled}
+	outcome, err = summary.outcome(stopped)
+	assert.Equal(t, OutcomeFatal, outcome)
+	assert.Equal(t, stopped, err)
+}
@@ -0,0 +1,48 @@
+package makemkv
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrInvalidOutput = errors.New("makemkv: invalid output path")
+
+// Output is where makemkvcon sends messages, progress or debug output. The
+// zero value leaves the choice to makemkvcon.
+type Output struct {
+	target string
+}
+
+var (
+	OutputStdout = Output{"-stdout"}
+	OutputStderr = Output{"-stderr"}
+	OutputNull   = Output{"-null"}
+	// OutputSame sends progress wherever messages go, it is not valid for
+	// messages themselves
+	OutputSame = Output{"-same"}
+)
+
+// OutputFile sends output to a file. Empty paths and paths starting with "-"
+// are rejected, makemkvcon would take the latter for one of the named targets;
+// spell them as "./-name" instead.
+func OutputFile(path string) (Output, error) {
+	if path == "" || strings.HasPrefix(path, "-") {
+		return Output{}, fmt.Errorf("%w: %q", ErrInvalidOutput, path)
+	}
+	return Output{path}, nil
+}
+
+// outputOf is the Output for a raw --messages style value, kept for the
+// deprecated string setters which never validated anything
+func outputOf(s string) Output {
+	return Output{s}
+}
+
+func (o Output) IsZero() bool {
+	return o.target == ""
+}
+
+func (o Output) String() string {
+	return o.target
+}
@@ -0,0 +1,129 @@
+package makemkv
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"time"
+)
+
+type ScanPhase int
+
+const (
+	PhaseOpen ScanPhase = iota
+	PhaseProtection
+	PhaseAnalysis
+)
+
+func (p ScanPhase) String() string {
+	switch p {
+	case PhaseOpen:
+		return "drive open"
+	case PhaseProtection:
+		return "protection processing"
+	case PhaseAnalysis:
+		return "title analysis"
+	default:
+		return "unknown"
+	}
+}
+
+type PhaseTimeoutError struct {
+	Phase   ScanPhase
+	Timeout time.Duration
+}
+
+func (e *PhaseTimeoutError) Error() string {
+	return fmt.Sprintf("makemkv: %s did not finish within %s", e.Phase, e.Timeout)
+}
+
+// PhaseCodes maps the message codes of the task names makemkvcon prints on
+// PRGT and PRGC lines onto scan phases. Names are translated, codes are not.
+// InfoJob.PhaseCodes adds to it for codes it lacks.
+var PhaseCodes = map[int]ScanPhase{
+	5018: PhaseOpen,       // Scanning CD-ROM devices
+	5047: PhaseOpen,       // Opening DVD disc
+	5049: PhaseOpen,       // Opening Blu-ray disc
+	5076: PhaseProtection, // Processing AACS keys
+	5078: PhaseProtection, // Processing BD+ code
+	5057: PhaseAnalysis,   // Processing title sets
+	5055: PhaseAnalysis,   // Analyzing seamless segments
+}
+
+// phaseWatch stops a job when it spends longer than allowed in one phase.
+// The clock restarts whenever the phase changes.
+type phaseWatch struct {
+	mu       sync.Mutex
+	timeouts map[ScanPhase]time.Duration
+	// looked up before PhaseCodes
+	codes map[int]ScanPhase
+	stop  func(StopReason, error)
+	phase ScanPhase
+	timer *time.Timer
+	// counts phase changes and the close, so a timer that fires late can
+	// tell it no longer applies
+	gen uint64
+}
+
+func newPhaseWatch(timeouts map[ScanPhase]time.Duration, codes map[int]ScanPhase, stop func(StopReason, error)) *phaseWatch {
+	w := &phaseWatch{timeouts: timeouts, codes: codes, stop: stop, phase: -1}
+	w.enter(PhaseOpen)
+	return w
+}
+
+func (w *phaseWatch) enter(phase ScanPhase) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if phase == w.phase {
+		return
+	}
+	w.phase = phase
+	w.reset()
+	if timeout, ok := w.timeouts[phase]; ok && timeout > 0 {
+		gen := w.gen
+		w.timer = time.AfterFunc(timeout, func() {
+			w.mu.Lock()
+			defer w.mu.Unlock()
+			if gen == w.gen {
+				w.stop(StopTimeout, &PhaseTimeoutError{Phase: phase, Timeout: timeout})
+			}
+		})
+	}
+}
+
+// reset stops the timer of the phase being left, which may already be
+// waiting for the lock to fire
+func (w *phaseWatch) reset() {
+	w.gen++
+	if w.timer != nil {
+		w.timer.Stop()
+		w.timer = nil
+	}
+}
+
+func (w *phaseWatch) classify(code int) (ScanPhase, bool) {
+	if phase, ok := w.codes[code]; ok {
+		return phase, true
+	}
+	phase, ok := PhaseCodes[code]
+	return phase, ok
+}
+
+func (w *phaseWatch) observe(prefix []byte, content []byte) {
+	if !bytes.Equal(prefix, []byte("PRGT")) && !bytes.Equal(prefix, []byte("PRGC")) {
+		return
+	}
+	code, _, ok := cutInt(content)
+	if !ok {
+		return
+	}
+	if phase, ok := w.classify(code); ok {
+		w.enter(phase)
+	}
+}
+
+func (w *phaseWatch) close() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.reset()
+}
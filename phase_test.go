@@ -0,0 +1,64 @@
+package makemkv
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPhaseWatchClassify(t *testing.T) {
+	watch := &phaseWatch{codes: map[int]ScanPhase{9001: PhaseAnalysis, 5018: PhaseAnalysis}}
+	for code, expected := range map[int]ScanPhase{
+		5049: PhaseOpen,
+		5076: PhaseProtection,
+		5057: PhaseAnalysis,
+		9001: PhaseAnalysis,
+		5018: PhaseAnalysis,
+	} {
+		phase, ok := watch.classify(code)
+		assert.True(t, ok, code)
+		assert.Equal(t, expected, phase, code)
+	}
+	_, ok := watch.classify(5017)
+	assert.False(t, ok)
+}
+
+func TestPhaseWatch(t *testing.T) {
+	stopped := make(chan error, 1)
+	watch := newPhaseWatch(map[ScanPhase]time.Duration{
+		PhaseOpen:       time.Hour,
+		PhaseProtection: 10 * time.Millisecond,
+	}, nil, func(reason StopReason, cause error) {
+		assert.Equal(t, StopTimeout, reason)
+		stopped <- cause
+	})
+	defer watch.close()
+
+	// the names don't matter, only the codes do
+	watch.observe([]byte("PRGT"), []byte(`5049,0,"Ouverture du disque Blu-ray"`))
+	watch.observe([]byte("PRGC"), []byte(`5076,0,"Traitement des clés AACS"`))
+	select {
+	case cause := <-stopped:
+		assert.Equal(t, &PhaseTimeoutError{Phase: PhaseProtection, Timeout: 10 * time.Millisecond}, cause)
+	case <-time.After(time.Second):
+		t.Fatal("phase timeout did not fire")
+	}
+}
+
+func TestPhaseWatchClosed(t *testing.T) {
+	stopped := make(chan error, 1)
+	watch := newPhaseWatch(map[ScanPhase]time.Duration{PhaseOpen: time.Millisecond}, nil, func(reason StopReason, cause error) {
+		stopped <- cause
+	})
+	// hold the lock past the deadline so the timer is already firing
+	watch.mu.Lock()
+	time.Sleep(5 * time.Millisecond)
+	watch.reset()
+	watch.mu.Unlock()
+	select {
+	case cause := <-stopped:
+		t.Fatalf("stale phase timeout: %v", cause)
+	case <-time.After(20 * time.Millisecond):
+	}
+}
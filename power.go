@@ -0,0 +1,159 @@
+package makemkv
+
+import (
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+var ErrNoDrive = errors.New("makemkv: no drive found for device")
+
+// DrivePower spins drives down once no job has used them for Idle and back
+// up before the next job, for machines that sit next to idle drives all
+// day. Set it on MkvOptions.Power, one DrivePower can be shared by the jobs
+// of every drive. Images are left alone.
+type DrivePower struct {
+	Idle time.Duration
+	// replace the built-in START STOP UNIT commands, which only exist on
+	// Linux, with something like sdparm
+	SpinDown func(device Device) error
+	SpinUp   func(device Device) error
+	// failures to change a drive's power state are logged here, they never
+	// fail a job
+	Logger *slog.Logger
+
+	mu     sync.Mutex
+	drives map[string]*drivePowerState
+}
+
+type drivePowerState struct {
+	active int
+	asleep bool
+	timer  *time.Timer
+	// held while a hook runs, so a drive is never spun up and down at once
+	// without holding up the other drives
+	hookMu sync.Mutex
+	// the device node for the built-in hooks, found once
+	path string
+}
+
+func (p *DrivePower) state(device Device) *drivePowerState {
+	key := device.Type() + ":" + device.Device()
+	if p.drives == nil {
+		p.drives = make(map[string]*drivePowerState)
+	}
+	s, ok := p.drives[key]
+	if !ok {
+		s = &drivePowerState{}
+		p.drives[key] = s
+	}
+	return s
+}
+
+// begin wakes the drive if it was spun down and keeps it up until end. opts
+// are the job's, used to find the drive's device node while it is awake.
+func (p *DrivePower) begin(device Device, opts MkvOptions) {
+	if p == nil || !DeviceCapabilities(device).Eject {
+		return
+	}
+	p.mu.Lock()
+	s := p.state(device)
+	s.active++
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+	wake := s.asleep
+	s.asleep = false
+	p.mu.Unlock()
+
+	s.hookMu.Lock()
+	defer s.hookMu.Unlock()
+	if s.path == "" && (p.SpinDown == nil || p.SpinUp == nil) {
+		if path, err := drivePath(device, opts); err == nil {
+			s.path = path
+		} else if p.Logger != nil {
+			p.Logger.Warn("makemkv drive not found", "device", device.Device(), "error", err)
+		}
+	}
+	if wake {
+		p.run(s, p.SpinUp, spinUp, device, "up")
+	}
+}
+
+// end starts the idle timer once the drive's last job is done
+func (p *DrivePower) end(device Device) {
+	if p == nil || !DeviceCapabilities(device).Eject {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	s := p.state(device)
+	s.active--
+	if s.active > 0 || p.Idle <= 0 {
+		return
+	}
+	s.timer = time.AfterFunc(p.Idle, func() {
+		p.mu.Lock()
+		if s.active > 0 || s.asleep {
+			p.mu.Unlock()
+			return
+		}
+		s.timer = nil
+		p.mu.Unlock()
+
+		s.hookMu.Lock()
+		defer s.hookMu.Unlock()
+		if !p.run(s, p.SpinDown, spinDown, device, "down") {
+			return
+		}
+		p.mu.Lock()
+		// a job that began while the drive was spinning down didn't know
+		// to wake it
+		wake := s.active > 0
+		s.asleep = !wake
+		p.mu.Unlock()
+		if wake {
+			p.run(s, p.SpinUp, spinUp, device, "up")
+		}
+	})
+}
+
+// run calls hook, or builtin on the drive's device node when there is no
+// hook, reporting whether it succeeded
+func (p *DrivePower) run(s *drivePowerState, hook func(Device) error, builtin func(string) error, device Device, direction string) bool {
+	var err error
+	switch {
+	case hook != nil:
+		err = hook(device)
+	case s.path == "":
+		err = ErrNoDrive
+	default:
+		err = builtin(s.path)
+	}
+	if err != nil && p.Logger != nil {
+		p.Logger.Warn("makemkv drive spin "+direction+" failed", "device", device.Device(), "error", err)
+	}
+	return err == nil
+}
+
+// drivePath finds the device node of a drive, which for drives given by
+// index means asking makemkvcon
+func drivePath(device Device, opts MkvOptions) (string, error) {
+	switch d := device.(type) {
+	case *DevDevice:
+		return d.Device(), nil
+	case *DiscDevice:
+		drives, err := ListDrives(MkvOptions{Audit: opts.Audit, Binary: opts.Binary, Env: opts.Env})
+		if err != nil {
+			return "", err
+		}
+		for _, drive := range drives {
+			if drive.Index == d.id && drive.DevicePath != "" {
+				return drive.DevicePath, nil
+			}
+		}
+	}
+	return "", ErrNoDrive
+}
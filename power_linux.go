@@ -0,0 +1,78 @@
+package makemkv
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// sg_io_hdr from <scsi/sg.h>
+type sgIoHdr struct {
+	interfaceId    int32
+	dxferDirection int32
+	cmdLen         uint8
+	mxSbLen        uint8
+	iovecCount     uint16
+	dxferLen       uint32
+	dxferp         unsafe.Pointer
+	cmdp           unsafe.Pointer
+	sbp            unsafe.Pointer
+	timeout        uint32
+	flags          uint32
+	packId         int32
+	usrPtr         unsafe.Pointer
+	status         uint8
+	maskedStatus   uint8
+	msgStatus      uint8
+	sbLenWr        uint8
+	hostStatus     uint16
+	driverStatus   uint16
+	resid          int32
+	duration       uint32
+	info           uint32
+}
+
+const (
+	sgIo        = 0x2285
+	sgDxferNone = -1
+	// START STOP UNIT, with the start bit in the fifth byte
+	startStopUnit = 0x1b
+)
+
+func spinDown(path string) error {
+	return startStop(path, false)
+}
+
+func spinUp(path string) error {
+	return startStop(path, true)
+}
+
+func startStop(path string, start bool) error {
+	f, err := os.OpenFile(path, os.O_RDONLY|syscall.O_NONBLOCK, 0)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	cmd := [6]byte{startStopUnit}
+	if start {
+		cmd[4] = 1
+	}
+	var sense [32]byte
+	hdr := sgIoHdr{
+		interfaceId:    'S',
+		dxferDirection: sgDxferNone,
+		cmdLen:         uint8(len(cmd)),
+		mxSbLen:        uint8(len(sense)),
+		cmdp:           unsafe.Pointer(&cmd[0]),
+		sbp:            unsafe.Pointer(&sense[0]),
+		timeout:        30000,
+	}
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, f.Fd(), sgIo, uintptr(unsafe.Pointer(&hdr))); errno != 0 {
+		return errno
+	}
+	if hdr.status != 0 || hdr.hostStatus != 0 || hdr.driverStatus != 0 {
+		return &os.PathError{Op: "start stop unit", Path: path, Err: syscall.EIO}
+	}
+	return nil
+}
@@ -0,0 +1,13 @@
+//go:build !linux
+
+package makemkv
+
+import "errors"
+
+func spinDown(path string) error {
+	return errors.ErrUnsupported
+}
+
+func spinUp(path string) error {
+	return errors.ErrUnsupported
+}
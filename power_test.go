@@ -0,0 +1,86 @@
+package makemkv
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDrivePower(t *testing.T) {
+	var mu sync.Mutex
+	var calls []string
+	record := func(call string) func(Device) error {
+		return func(Device) error {
+			mu.Lock()
+			defer mu.Unlock()
+			calls = append(calls, call)
+			return nil
+		}
+	}
+	recorded := func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), calls...)
+	}
+	power := &DrivePower{Idle: 20 * time.Millisecond, SpinDown: record("down"), SpinUp: record("up")}
+	drive := NewDiscDevice(0)
+
+	power.begin(drive, MkvOptions{})
+	power.begin(drive, MkvOptions{})
+	power.end(drive)
+	time.Sleep(50 * time.Millisecond)
+	assert.Nil(t, recorded(), "still in use by a job")
+
+	power.end(drive)
+	assert.Eventually(t, func() bool { return len(recorded()) == 1 }, time.Second, 5*time.Millisecond)
+	power.begin(drive, MkvOptions{})
+	assert.Equal(t, []string{"down", "up"}, recorded())
+
+	// a job starting before the timer fires keeps the drive spinning
+	power.end(drive)
+	power.begin(drive, MkvOptions{})
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, []string{"down", "up"}, recorded())
+	power.end(drive)
+
+	image := NewIsoDevice("/disc.iso")
+	var none *DrivePower
+	none.begin(image, MkvOptions{})
+	none.end(image)
+	power.begin(image, MkvOptions{})
+	power.end(image)
+}
+
+func TestDrivePowerFailedSpinDown(t *testing.T) {
+	var ups, downs atomic.Int32
+	other := NewDiscDevice(1)
+	var power *DrivePower
+	power = &DrivePower{
+		Idle: 10 * time.Millisecond,
+		SpinDown: func(device Device) error {
+			if device == other {
+				return nil
+			}
+			// hooks run without holding up the other drives
+			power.begin(other, MkvOptions{})
+			power.end(other)
+			downs.Add(1)
+			return errors.New("busy")
+		},
+		SpinUp: func(Device) error {
+			ups.Add(1)
+			return nil
+		},
+	}
+	drive := NewDiscDevice(0)
+	power.begin(drive, MkvOptions{})
+	power.end(drive)
+	assert.Eventually(t, func() bool { return downs.Load() >= 1 }, time.Second, 5*time.Millisecond)
+	power.begin(drive, MkvOptions{})
+	power.end(drive)
+	assert.Equal(t, int32(0), ups.Load(), "a drive that failed to spin down is not woken")
+}
@@ -0,0 +1,29 @@
+//go:build unix
+
+package makemkv
+
+import (
+	"errors"
+	"os/exec"
+	"syscall"
+)
+
+// makemkvcon is started as the leader of its own process group so that any
+// helpers it spawns can be signalled together with it
+func setProcessGroup(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Setpgid = true
+}
+
+func killProcessGroup(cmd *exec.Cmd) error {
+	if cmd.Process == nil {
+		return nil
+	}
+	err := syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	if errors.Is(err, syscall.ESRCH) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,154 @@
+package makemkv
+
+import (
+	"encoding/xml"
+	"io"
+)
+
+// Profile is a MakeMKV conversion profile, written out and passed with
+// --profile when set on MkvOptions
+type Profile struct {
+	Name string
+	// MakeMKV's selection rules, like "-sel:all,+sel:(favlang|nolang)",
+	// used by tracks without a selection of their own. MakeMKV's preference
+	// applies when empty.
+	DefaultSelection string
+	Mkv              ProfileMkvSettings
+	Outputs          []ProfileOutput
+	Tracks           []ProfileTrack
+}
+
+// ProfileMkvSettings are the flags written to every mkv, nil for MakeMKV's
+// default
+type ProfileMkvSettings struct {
+	IgnoreForcedSubtitlesFlag            *bool
+	UseISO639Type2T                      *bool
+	SetFirstAudioTrackAsDefault          *bool
+	SetFirstSubtitleTrackAsDefault       *bool
+	SetFirstForcedSubtitleTrackAsDefault *bool
+	InsertFirstChapter00IfMissing        *bool
+}
+
+// ProfileOutput is a named way of writing a track, Format being one of
+// MakeMKV's output formats like "directCopy", "LPCM-raw" or "FLAC"
+type ProfileOutput struct {
+	Name        string
+	Format      string
+	Description string
+	// passed on to the encoder, like "-compression_level 12"
+	ExtraArgs string
+}
+
+// ProfileTrack sends tracks of an input kind, like "default", "LPCM-stereo"
+// or "LPCM-multi", to the output named Output
+type ProfileTrack struct {
+	Input  string
+	Output string
+	// the selection rules for these tracks, DefaultSelection when empty
+	Selection string
+}
+
+// DefaultProfile is the profile MakeMKV ships as default.mmcp.xml, a
+// starting point for changing only part of it
+func DefaultProfile() *Profile {
+	return &Profile{
+		Name: "Default",
+		Mkv: ProfileMkvSettings{
+			IgnoreForcedSubtitlesFlag:            Ptr(true),
+			UseISO639Type2T:                      Ptr(false),
+			SetFirstAudioTrackAsDefault:          Ptr(true),
+			SetFirstSubtitleTrackAsDefault:       Ptr(true),
+			SetFirstForcedSubtitleTrackAsDefault: Ptr(true),
+			InsertFirstChapter00IfMissing:        Ptr(true),
+		},
+		Outputs: []ProfileOutput{
+			{Name: "copy", Format: "directCopy", Description: "Copy track as is"},
+			{Name: "lpcm", Format: "LPCM-raw", Description: "Save as raw LPCM"},
+			{Name: "wavex", Format: "LPCM-wavex", Description: "Save as LPCM in WAV container"},
+			{Name: "flac-best", Format: "FLAC", Description: "Save as FLAC (best compression)", ExtraArgs: "-compression_level 12"},
+			{Name: "flac-fast", Format: "FLAC", Description: "Save as FLAC (fast compression)", ExtraArgs: "-compression_level 5"},
+		},
+		Tracks: []ProfileTrack{
+			{Input: "default", Output: "copy"},
+			{Input: "LPCM-stereo", Output: "lpcm"},
+			{Input: "LPCM-multi", Output: "wavex"},
+		},
+	}
+}
+
+type xmlProfile struct {
+	XMLName         xml.Name            `xml:"profile"`
+	Name            string              `xml:"name"`
+	MkvSettings     xmlMkvSettings      `xml:"mkvSettings"`
+	ProfileSettings *xmlProfileSettings `xml:"profileSettings"`
+	Outputs         []xmlOutput         `xml:"outputSettings"`
+	Tracks          []xmlTrack          `xml:"trackSettings"`
+}
+
+type xmlProfileSettings struct {
+	Selection string `xml:"app_DefaultSelectionString,attr"`
+}
+
+type xmlMkvSettings struct {
+	IgnoreForcedSubtitlesFlag            *bool `xml:"ignoreForcedSubtitlesFlag,attr,omitempty"`
+	UseISO639Type2T                      *bool `xml:"useISO639Type2T,attr,omitempty"`
+	SetFirstAudioTrackAsDefault          *bool `xml:"setFirstAudioTrackAsDefault,attr,omitempty"`
+	SetFirstSubtitleTrackAsDefault       *bool `xml:"setFirstSubtitleTrackAsDefault,attr,omitempty"`
+	SetFirstForcedSubtitleTrackAsDefault *bool `xml:"setFirstForcedSubtitleTrackAsDefault,attr,omitempty"`
+	InsertFirstChapter00IfMissing        *bool `xml:"insertFirstChapter00IfMissing,attr,omitempty"`
+}
+
+type xmlOutput struct {
+	Name        string          `xml:"name,attr"`
+	Format      string          `xml:"outputFormat,attr"`
+	Description *xmlDescription `xml:"description"`
+	ExtraArgs   string          `xml:"extraArgs,omitempty"`
+}
+
+type xmlDescription struct {
+	Lang string `xml:"lang,attr"`
+	Text string `xml:",chardata"`
+}
+
+type xmlTrack struct {
+	Input  string `xml:"input,attr"`
+	Output struct {
+		Name      string `xml:"outputSettingsName,attr"`
+		Selection string `xml:"defaultSelection,attr"`
+	} `xml:"output"`
+}
+
+// Write writes the profile as a .mmcp.xml file
+func (p *Profile) Write(w io.Writer) error {
+	doc := xmlProfile{Name: p.Name, MkvSettings: xmlMkvSettings(p.Mkv)}
+	if p.DefaultSelection != "" {
+		doc.ProfileSettings = &xmlProfileSettings{Selection: p.DefaultSelection}
+	}
+	for _, o := range p.Outputs {
+		out := xmlOutput{Name: o.Name, Format: o.Format, ExtraArgs: o.ExtraArgs}
+		if o.Description != "" {
+			out.Description = &xmlDescription{Lang: "eng", Text: o.Description}
+		}
+		doc.Outputs = append(doc.Outputs, out)
+	}
+	for _, t := range p.Tracks {
+		track := xmlTrack{Input: t.Input}
+		track.Output.Name = t.Output
+		track.Output.Selection = t.Selection
+		if track.Output.Selection == "" {
+			track.Output.Selection = "$app_DefaultSelectionString"
+		}
+		doc.Tracks = append(doc.Tracks, track)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "    ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,54 @@
+package makemkv
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProfileWrite(t *testing.T) {
+	profile := &Profile{
+		Name:             "Flac",
+		DefaultSelection: "-sel:all,+sel:(favlang|nolang)",
+		Mkv:              ProfileMkvSettings{SetFirstAudioTrackAsDefault: Ptr(true)},
+		Outputs:          []ProfileOutput{{Name: "flac", Format: "FLAC", ExtraArgs: "-compression_level 8"}},
+		Tracks:           []ProfileTrack{{Input: "default", Output: "flac", Selection: "+sel:audio"}, {Input: "LPCM-multi", Output: "flac"}},
+	}
+	var buf bytes.Buffer
+	assert.Nil(t, profile.Write(&buf))
+	assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<profile>
+    <name>Flac</name>
+    <mkvSettings setFirstAudioTrackAsDefault="true"></mkvSettings>
+    <profileSettings app_DefaultSelectionString="-sel:all,+sel:(favlang|nolang)"></profileSettings>
+    <outputSettings name="flac" outputFormat="FLAC">
+        <extraArgs>-compression_level 8</extraArgs>
+    </outputSettings>
+    <trackSettings input="default">
+        <output outputSettingsName="flac" defaultSelection="+sel:audio"></output>
+    </trackSettings>
+    <trackSettings input="LPCM-multi">
+        <output outputSettingsName="flac" defaultSelection="$app_DefaultSelectionString"></output>
+    </trackSettings>
+</profile>
+`, buf.String())
+}
+
+func TestWithProfile(t *testing.T) {
+	opts, _, cleanup, err := MkvOptions{Profile: DefaultProfile()}.withTransport()
+	if assert.Nil(t, err) {
+		assert.Contains(t, opts.toStrings(), "--profile="+opts.ProfileFile)
+		data, err := os.ReadFile(opts.ProfileFile)
+		assert.Nil(t, err)
+		assert.Contains(t, string(data), `<outputSettings name="flac-best" outputFormat="FLAC">`)
+		cleanup()
+		_, err = os.Stat(opts.ProfileFile)
+		assert.True(t, os.IsNotExist(err))
+	}
+
+	_, _, _, err = MkvOptions{Profile: DefaultProfile(), ProfileFile: "my.mmcp.xml"}.withTransport()
+	assert.ErrorIs(t, err, ErrConflictingOptions)
+	assert.Contains(t, MkvOptions{ProfileFile: "my.mmcp.xml"}.toStrings(), "--profile=my.mmcp.xml")
+}
@@ -0,0 +1,268 @@
+package makemkv
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"log/slog"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// progress is what a job that reports statuses while makemkvcon runs hands
+// to runWithProgress
+type progress struct {
+	ch           chan Status
+	messages     chan Message
+	delivery     DeliveryPolicy
+	includeRaw   bool
+	thresholds   Thresholds
+	stopper      *stopper
+	logger       *slog.Logger
+	logStep      int
+	startTimeout time.Duration
+	// the Seq to carry on from, for a job running makemkvcon again
+	seq uint64
+	// the title being ripped, "" when makemkvcon is saving every title
+	saving string
+}
+
+// sendFinal ends ch with final under policy, returning how many statuses
+// were dropped to make room for it
+func sendFinal(ch chan Status, policy DeliveryPolicy, final Status) uint64 {
+	sender := statusSender{ch: ch, policy: policy}
+	sender.final(final)
+	return sender.dropped
+}
+
+// progressParser holds what has been gathered from mkv or backup output so
+// far, a line at a time
+type progressParser struct {
+	title       string
+	channel     string
+	titleCode   int
+	channelCode int
+	// the id of the title being saved, see Status.SavingTitle
+	saving string
+	// the last PRGV values
+	current  int
+	total    int
+	max      int
+	seq      uint64
+	version  Version
+	summary  ripSummary
+	counter  messageCounter
+	failures failureWatch
+
+	includeRaw bool
+	// each called when not nil
+	status  func(Status)
+	message func(Message)
+	log     *progressLog
+	report  ParseReport
+}
+
+func (p *progressParser) line(line string) {
+	prefix, content, found := strings.Cut(line, ":")
+	if !found {
+		p.report.line(prefixNone, nil, len(line))
+		return
+	}
+	kind := parsePrefix([]byte(prefix))
+	p.report.line(kind, []byte(prefix), len(line))
+	p.parse(kind, content, line)
+}
+
+// parse handles the content of a line of kind that has already been
+// counted, raw being the whole line for Status.Raw
+func (p *progressParser) parse(kind linePrefix, content string, raw string) {
+	parts := splitQuoted(content)
+	complete := p.report.fields(protocolFor(p.version), kind, len(parts))
+	switch kind {
+	case prefixMSG:
+		msg, ok := parseMessage(content)
+		if !ok {
+			p.report.Malformed++
+			return
+		}
+		if msg.Code == msgStarted && p.version.IsZero() {
+			p.version, _ = parseVersion(msg.Text)
+		}
+		if id, ok := savingTitle(msg); ok {
+			p.saving = id
+		}
+		p.summary.observe(msg)
+		p.counter.observe(msg)
+		p.failures.observe(msg)
+		if p.message != nil {
+			p.message(msg)
+		}
+	case prefixDRV:
+		if drive, ok := parseDrive(content, protocolFor(p.version)); ok {
+			p.summary.drives = append(p.summary.drives, drive)
+		}
+	case prefixPRGT:
+		p.titleCode, _ = strconv.Atoi(field(parts, 0))
+		p.title = field(parts, 2)
+	case prefixPRGC:
+		p.channelCode, _ = strconv.Atoi(field(parts, 0))
+		p.channel = field(parts, 2)
+	case prefixPRGV:
+		current, err1 := strconv.Atoi(field(parts, 0))
+		total, err2 := strconv.Atoi(field(parts, 1))
+		max, err3 := strconv.Atoi(field(parts, 2))
+		if complete && (err1 != nil || err2 != nil || err3 != nil) {
+			p.report.Malformed++
+		}
+		p.current, p.total, p.max = current, total, max
+		p.log.observe(p.title, total, max)
+		if p.status != nil {
+			status := Status{
+				Title:       p.title,
+				Channel:     p.channel,
+				TitleCode:   p.titleCode,
+				ChannelCode: p.channelCode,
+				Current:     current,
+				Total:       total,
+				Max:         max,
+				Seq:         p.next(),
+				SavingTitle: p.saving,
+			}
+			if p.includeRaw {
+				status.Raw = raw
+			}
+			p.status(status)
+		}
+	}
+}
+
+// next numbers an event that is about to be sent, statuses and messages
+// share the count so they can be put back in order
+func (p *progressParser) next() uint64 {
+	p.seq++
+	return p.seq
+}
+
+// final is the status ending a job, repeating the last progress. reason is
+// why the job was stopped, StopNone when it wasn't.
+func (p *progressParser) final(reason StopReason) Status {
+	return Status{
+		Title:       p.title,
+		Channel:     p.channel,
+		TitleCode:   p.titleCode,
+		ChannelCode: p.channelCode,
+		Current:     p.current,
+		Total:       p.total,
+		Max:         p.max,
+		Seq:         p.seq + 1,
+		SavingTitle: p.saving,
+		Final:       true,
+		Stopped:     reason,
+	}
+}
+
+// result fills in what the output says about the job, err being how the
+// process ended
+func (p *progressParser) result(result *RipResult, err error) error {
+	result.Version = p.version
+	result.Report = p.report
+	result.RepeatedMessages = p.counter.repeated()
+	result.Outcome, err = p.summary.outcome(err)
+	result.Saved, result.Failed = p.summary.saved, p.summary.failedCount()
+	return p.failures.wrap(err)
+}
+
+// ParseMkvOutput parses robot output captured from makemkvcon mkv or backup,
+// returning the statuses it reports and what it says about the job. The
+// error is only for failing to read r, the outcome is on the result.
+func ParseMkvOutput(r io.Reader) ([]Status, *RipResult, error) {
+	var statuses []Status
+	parser := progressParser{status: func(s Status) { statuses = append(statuses, s) }}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		parser.line(scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return statuses, nil, err
+	}
+	result := &RipResult{}
+	parser.result(result, nil)
+	return statuses, result, nil
+}
+
+// liveMessage passes msg on to the job's Messagechan and, for updates about
+// repeated messages, its Logger
+func (p progress) liveMessage(parser *progressParser, msg Message) {
+	if p.messages != nil {
+		msg.Seq = parser.next()
+		p.messages <- msg
+	}
+	if msg.Count > 0 && p.logger != nil {
+		p.logger.Info("makemkv repeated message", "code", msg.Code, "text", msg.Text, "count", msg.Count)
+	}
+}
+
+// runWithProgress runs cmd, sending statuses and watching messages as they
+// come, and returns the result along with what makemkvcon said about saved
+// titles for the job to check against its own expectations
+func runWithProgress(cmd *exec.Cmd, file string, audit AuditLog, p progress) (*RipResult, ripSummary, error) {
+	start := time.Now()
+	watch := thresholdWatch{limits: p.thresholds}
+	sender := statusSender{ch: p.ch, policy: p.delivery}
+	var parser progressParser
+	parser = progressParser{
+		seq:        p.seq,
+		saving:     p.saving,
+		includeRaw: p.includeRaw,
+		log:        newProgressLog(p.logger, p.logStep),
+		message: func(msg Message) {
+			if err := watch.observe(msg); err != nil {
+				p.stopper.stopCause(StopThreshold, err)
+			}
+			if live, ok := parser.counter.live(msg, time.Now(), repeatInterval); ok {
+				p.liveMessage(&parser, live)
+			}
+		},
+	}
+	if p.ch != nil {
+		parser.status = sender.send
+	}
+
+	parseErr, err := runCommand(cmd, p.stopper, file, audit, func(out io.Reader) error {
+		start := newStartWatch(p.startTimeout, p.stopper.stopCause)
+		defer start.close()
+		scanner := bufio.NewScanner(out)
+		for scanner.Scan() {
+			line := scanner.Text()
+			start.output(strings.HasPrefix(line, "MSG:"+string(bannerPrefix)))
+			parser.line(line)
+		}
+		return scanner.Err()
+	})
+	if err == nil {
+		err = parseErr
+	}
+
+	result := &RipResult{
+		WallTime:        time.Since(start),
+		DroppedStatuses: sender.dropped,
+	}
+	if state := cmd.ProcessState; state != nil {
+		result.UserTime = state.UserTime()
+		result.SystemTime = state.SystemTime()
+		result.MaxRSS = maxRSS(state)
+	}
+	for _, update := range parser.counter.pending() {
+		p.liveMessage(&parser, update)
+	}
+	var reason StopReason
+	var stopped *StoppedError
+	if errors.As(err, &stopped) {
+		reason = stopped.Reason
+	}
+	parser.summary.final = parser.final(reason)
+	err = parser.result(result, err)
+	return result, parser.summary, err
+}
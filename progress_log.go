@@ -0,0 +1,35 @@
+package makemkv
+
+import "log/slog"
+
+const defaultLogStep = 5
+
+// progressLog logs overall progress each time it passes another step percent
+// rather than on every PRGV line
+type progressLog struct {
+	logger *slog.Logger
+	step   int
+	next   int
+}
+
+func newProgressLog(logger *slog.Logger, step int) *progressLog {
+	if logger == nil {
+		return nil
+	}
+	if step <= 0 {
+		step = defaultLogStep
+	}
+	return &progressLog{logger: logger, step: step}
+}
+
+func (l *progressLog) observe(task string, total int, max int) {
+	if l == nil || max <= 0 {
+		return
+	}
+	percent := total * 100 / max
+	if percent < l.next {
+		return
+	}
+	l.logger.Info("makemkv progress", "percent", percent, "task", task)
+	l.next = (percent/l.step + 1) * l.step
+}
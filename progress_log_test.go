@@ -0,0 +1,34 @@
+package makemkv
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProgressLog(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+	log := newProgressLog(logger, 25)
+	for total := 0; total <= 1000; total += 10 {
+		log.observe("Saving to MKV file", total, 1000)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Equal(t, 5, len(lines))
+	assert.Equal(t, `level=INFO msg="makemkv progress" percent=0 task="Saving to MKV file"`, lines[0])
+	assert.Contains(t, lines[1], "percent=25")
+	assert.Contains(t, lines[4], "percent=100")
+
+	// no logger, nothing to do
+	newProgressLog(nil, 0).observe("ignored", 1, 2)
+}
@@ -0,0 +1,67 @@
+package makemkv
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMkvOutput(t *testing.T) {
+	statuses, result, err := ParseMkvOutput(strings.NewReader(`MSG:1005,0,1,"MakeMKV v1.17.6 linux(x64-release) started","%1 started","MakeMKV v1.17.6 linux(x64-release)"
+PRGT:5018,0,"Saving to MKV file"
+PRGC:5017,0,"Saving to MKV file"
+PRGV:0,0,65536
+PRGV:32768,32768,65536
+MSG:5003,0,2,"Failed to save title 1 to file /out/t01.mkv","Failed to save title %1 to file %2","1","/out/t01.mkv"
+PRGV:65536,65536,65536
+MSG:5036,0,2,"Copy complete. 1 titles saved, 1 failed.","Copy complete. %1 titles saved, %2 failed.","1","1"
+`))
+	assert.Nil(t, err)
+	if assert.Equal(t, 3, len(statuses)) {
+		assert.Equal(t, uint64(3), statuses[2].Seq)
+		assert.Equal(t, 65536, statuses[2].Total)
+		assert.Equal(t, "Saving to MKV file", statuses[2].Title)
+		assert.Equal(t, 5018, statuses[2].TitleCode)
+		assert.Equal(t, 5017, statuses[2].ChannelCode)
+	}
+	assert.Equal(t, Version{Major: 1, Minor: 17, Patch: 6}, result.Version)
+	assert.Equal(t, OutcomePartial, result.Outcome)
+	assert.Equal(t, 1, result.Saved)
+	assert.Equal(t, 1, result.Failed)
+	assert.Equal(t, 8, result.Report.Lines)
+	assert.Nil(t, result.Report.UnknownPrefixes)
+	assert.Equal(t, 0, result.Report.Malformed)
+}
+
+func TestProgressParserResult(t *testing.T) {
+	var parser progressParser
+	for _, line := range []string{
+		`MSG:5003,0,2,"Failed to save title 1 to file /out/t01.mkv","Failed to save title %1 to file %2","1","/out/t01.mkv"`,
+		`MSG:5036,0,2,"Copy complete. 1 titles saved, 1 failed.","Copy complete. %1 titles saved, %2 failed.","1","1"`,
+	} {
+		parser.line(line)
+	}
+	var result RipResult
+	err := parser.result(&result, nil)
+	partial, ok := err.(*PartialSuccessError)
+	if assert.True(t, ok) {
+		assert.Nil(t, partial.Err, "wrapped once")
+		assert.Equal(t, []int{1}, partial.Titles)
+	}
+}
+
+func TestProgressParserSavingTitle(t *testing.T) {
+	statuses, _, err := ParseMkvOutput(strings.NewReader(`PRGV:0,0,65536
+MSG:5005,0,2,"Saving title 0 into file /out/title_t00.mkv","Saving title %1 into file %2","0","/out/title_t00.mkv"
+PRGV:100,100,65536
+MSG:5005,0,2,"Saving title 2 into file /out/title_t02.mkv","Saving title %1 into file %2","2","/out/title_t02.mkv"
+PRGV:200,200,65536
+`))
+	assert.Nil(t, err)
+	var saving []string
+	for _, status := range statuses {
+		saving = append(saving, status.SavingTitle)
+	}
+	assert.Equal(t, []string{"", "0", "2"}, saving)
+}
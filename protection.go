@@ -0,0 +1,40 @@
+package makemkv
+
+import "strings"
+
+type Protection struct {
+	// Present is set when the scan mentioned AACS, BD+ or CSS at all
+	Present bool
+	// Handled is false when any of those messages reported a failure
+	Handled bool
+	// Detail holds the messages the above were derived from
+	Detail []string
+}
+
+var protectionSchemes = []string{"AACS", "BD+", "CSS", "decrypt"}
+
+var protectionFailures = []string{"fail", "Fail", "unable", "Unable", "not be decrypted", "error", "Error"}
+
+func (p *Protection) observe(msg Message) {
+	text := msg.Text
+	if !containsAny(text, protectionSchemes) {
+		return
+	}
+	if !p.Present {
+		p.Present = true
+		p.Handled = true
+	}
+	if containsAny(text, protectionFailures) {
+		p.Handled = false
+	}
+	p.Detail = append(p.Detail, text)
+}
+
+func containsAny(s string, needles []string) bool {
+	for _, needle := range needles {
+		if strings.Contains(s, needle) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,34 @@
+package makemkv
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDiscInfoProtection(t *testing.T) {
+	result, err := parseDiscInfo(bufio.NewScanner(strings.NewReader(input)))
+	assert.Nil(t, err)
+	assert.Equal(t, Protection{}, result.Protection)
+
+	result, err = parseDiscInfo(bufio.NewScanner(strings.NewReader(`MSG:3007,0,0,"Using direct disc access mode","Using direct disc access mode"
+MSG:3344,0,0,"Processing AACS keys","Processing AACS keys"
+`)))
+	assert.Nil(t, err)
+	assert.Equal(t, Protection{Present: true, Handled: true, Detail: []string{"Processing AACS keys"}}, result.Protection)
+
+	result, err = parseDiscInfo(bufio.NewScanner(strings.NewReader(`MSG:3344,0,0,"Processing AACS keys","Processing AACS keys"
+MSG:5021,0,0,"AACS authentication failed","AACS authentication failed"
+`)))
+	assert.Nil(t, err)
+	assert.True(t, result.Protection.Present)
+	assert.False(t, result.Protection.Handled)
+	assert.Equal(t, 2, len(result.Protection.Detail))
+
+	result, err = parseDiscInfo(bufio.NewScanner(strings.NewReader(`MSG:3344,0,1,"Processing AACS keys, \"volume\" 1","Processing AACS keys, %1","\"volume\" 1"
+`)))
+	assert.Nil(t, err)
+	assert.Equal(t, []string{`Processing AACS keys, "volume" 1`}, result.Protection.Detail)
+}
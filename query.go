@@ -0,0 +1,81 @@
+package makemkv
+
+import (
+	"strings"
+	"time"
+)
+
+// stream flags from apdefs.h
+const (
+	ap_AVStreamFlag_CoreAudio       = 256
+	ap_AVStreamFlag_ForcedSubtitles = 4096
+)
+
+func (t *TitleInfo) AudioByLang(lang string) []*AudioStreamInfo {
+	var streams []*AudioStreamInfo
+	for i := range t.AudioStreams {
+		if strings.EqualFold(t.AudioStreams[i].LangCode, lang) {
+			streams = append(streams, &t.AudioStreams[i])
+		}
+	}
+	return streams
+}
+
+func (t *TitleInfo) SubtitlesByLang(lang string) []*SubtitleStreamInfo {
+	var streams []*SubtitleStreamInfo
+	for i := range t.SubtitleStreams {
+		if strings.EqualFold(t.SubtitleStreams[i].LangCode, lang) {
+			streams = append(streams, &t.SubtitleStreams[i])
+		}
+	}
+	return streams
+}
+
+func (t *TitleInfo) SubtitlesForced() []*SubtitleStreamInfo {
+	var streams []*SubtitleStreamInfo
+	for i := range t.SubtitleStreams {
+		if t.SubtitleStreams[i].Forced() {
+			streams = append(streams, &t.SubtitleStreams[i])
+		}
+	}
+	return streams
+}
+
+func (s *SubtitleStreamInfo) Forced() bool {
+	return s.StreamFlags&ap_AVStreamFlag_ForcedSubtitles != 0
+}
+
+// Lossless is false for the lossy core makemkvcon splits out of a lossless
+// track, even though both come from the same source stream
+func (a *AudioStreamInfo) Lossless() bool {
+	if a.StreamFlags&ap_AVStreamFlag_CoreAudio != 0 {
+		return false
+	}
+	switch {
+	case strings.HasPrefix(a.CodecId, "A_TRUEHD"),
+		strings.HasPrefix(a.CodecId, "A_FLAC"),
+		strings.HasPrefix(a.CodecId, "A_PCM"),
+		strings.HasPrefix(a.CodecId, "A_MLP"):
+		return true
+	}
+	return a.CodecShort == "DTS-HD MA" || a.CodecShort == "LPCM"
+}
+
+func (t *TitleInfo) HasLosslessAudio() bool {
+	for i := range t.AudioStreams {
+		if t.AudioStreams[i].Lossless() {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *DiscInfo) TitlesLongerThan(duration time.Duration) []*TitleInfo {
+	var titles []*TitleInfo
+	for i := range d.Titles {
+		if d.Titles[i].Duration > duration {
+			titles = append(titles, &d.Titles[i])
+		}
+	}
+	return titles
+}
@@ -0,0 +1,54 @@
+package makemkv
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTitleQueries(t *testing.T) {
+	title := TitleInfo{
+		AudioStreams: []AudioStreamInfo{
+			{Id: 1, LangCode: "eng", CodecId: "A_TRUEHD", CodecShort: "TrueHD"},
+			{Id: 2, LangCode: "eng", CodecId: "A_AC3", CodecShort: "AC3", StreamFlags: ap_AVStreamFlag_CoreAudio},
+			{Id: 3, LangCode: "fra", CodecId: "A_DTS", CodecShort: "DTS"},
+		},
+		SubtitleStreams: []SubtitleStreamInfo{
+			{Id: 4, LangCode: "eng", StreamFlags: 6144},
+			{Id: 5, LangCode: "eng"},
+		},
+	}
+
+	audio := title.AudioByLang("ENG")
+	if assert.Equal(t, 2, len(audio)) {
+		assert.Equal(t, 1, audio[0].Id)
+		assert.Equal(t, 2, audio[1].Id)
+	}
+	assert.Equal(t, 0, len(title.AudioByLang("deu")))
+	assert.Equal(t, 2, len(title.SubtitlesByLang("eng")))
+
+	forced := title.SubtitlesForced()
+	if assert.Equal(t, 1, len(forced)) {
+		assert.Equal(t, 4, forced[0].Id)
+	}
+
+	assert.True(t, title.HasLosslessAudio())
+	assert.True(t, title.AudioStreams[0].Lossless())
+	assert.False(t, title.AudioStreams[1].Lossless())
+	title.AudioStreams = title.AudioStreams[1:]
+	assert.False(t, title.HasLosslessAudio())
+	assert.True(t, (&AudioStreamInfo{CodecId: "A_DTS", CodecShort: "DTS-HD MA"}).Lossless())
+}
+
+func TestTitlesLongerThan(t *testing.T) {
+	disc := DiscInfo{Titles: []TitleInfo{
+		{Id: 0, Duration: 2 * time.Hour},
+		{Id: 1, Duration: 90 * time.Minute},
+		{Id: 2, Duration: 20 * time.Minute},
+	}}
+	titles := disc.TitlesLongerThan(90 * time.Minute)
+	if assert.Equal(t, 1, len(titles)) {
+		assert.Equal(t, 0, titles[0].Id)
+	}
+}
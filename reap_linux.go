@@ -0,0 +1,86 @@
+package makemkv
+
+import (
+	"bytes"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// ReapOrphans kills the process groups of makemkvcon processes this package
+// started whose parent is gone, which is what is left behind when an
+// embedding application crashes mid-job. Only the current user's processes
+// are considered, and an orphan counts as such whether it was reparented to
+// init or to a subreaper. It returns the pids that were killed.
+func ReapOrphans() ([]int, error) {
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		return nil, err
+	}
+
+	uid := os.Getuid()
+	var reaped []int
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+		if info, err := entry.Info(); err != nil || int(info.Sys().(*syscall.Stat_t).Uid) != uid {
+			continue
+		}
+		parent, ok := readProcParent(pid)
+		if !ok {
+			continue
+		}
+		// started by this package as a process group leader, from a
+		// process that is no longer its parent
+		ppid, pgrp, ok := readProcStat(pid)
+		if !ok || pgrp != pid || ppid == parent {
+			continue
+		}
+		if err := syscall.Kill(-pid, syscall.SIGKILL); err != nil {
+			continue
+		}
+		reaped = append(reaped, pid)
+	}
+	return reaped, nil
+}
+
+// readProcParent returns the pid newCommand recorded in the process's
+// environment, ok is false for processes this package didn't start
+func readProcParent(pid int) (int, bool) {
+	environ, err := os.ReadFile("/proc/" + strconv.Itoa(pid) + "/environ")
+	if err != nil {
+		return 0, false
+	}
+	for _, v := range bytes.Split(environ, []byte{0}) {
+		if value, found := bytes.CutPrefix(v, []byte(parentEnv+"=")); found {
+			parent, err := strconv.Atoi(string(value))
+			return parent, err == nil
+		}
+	}
+	return 0, false
+}
+
+func readProcStat(pid int) (ppid int, pgrp int, ok bool) {
+	stat, err := os.ReadFile("/proc/" + strconv.Itoa(pid) + "/stat")
+	if err != nil {
+		return ppid, pgrp, false
+	}
+
+	// the comm field is wrapped in parens and may itself contain spaces or parens
+	content := string(stat)
+	end := strings.LastIndexByte(content, ')')
+	if end < 0 {
+		return ppid, pgrp, false
+	}
+
+	fields := strings.Fields(content[end+1:])
+	if len(fields) < 3 {
+		return ppid, pgrp, false
+	}
+	ppid, err1 := strconv.Atoi(fields[1])
+	pgrp, err2 := strconv.Atoi(fields[2])
+	return ppid, pgrp, err1 == nil && err2 == nil
+}
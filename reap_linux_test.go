@@ -0,0 +1,46 @@
+package makemkv
+
+import (
+	"os/exec"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/aravance/go-makemkv/makemkvtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReapOrphans(t *testing.T) {
+	opts := fakeMakemkvcon(t, "", 0)
+	opts.Env = append(opts.Env, makemkvtest.Hold(time.Minute))
+	start := func(parent int) *exec.Cmd {
+		cmd := newCommand(opts, "info", "disc:0")
+		if parent != 0 {
+			cmd.Env = append(cmd.Env, parentEnv+"="+strconv.Itoa(parent))
+		}
+		if err := cmd.Start(); err != nil {
+			t.Fatal(err)
+		}
+		return cmd
+	}
+	mine := start(0)
+	defer func() {
+		killProcessGroup(mine)
+		mine.Wait()
+	}()
+	// started by a process that has since gone, whatever adopted it
+	orphan := start(1)
+
+	reaped, err := ReapOrphans()
+	assert.Nil(t, err)
+	if !assert.Contains(t, reaped, orphan.Process.Pid) {
+		killProcessGroup(orphan)
+	}
+	assert.NotContains(t, reaped, mine.Process.Pid)
+
+	err = orphan.Wait()
+	if exitErr, ok := err.(*exec.ExitError); assert.True(t, ok, "orphan was killed") {
+		assert.False(t, exitErr.Success())
+	}
+	assert.Nil(t, mine.ProcessState)
+}
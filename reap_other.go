@@ -0,0 +1,9 @@
+//go:build !linux
+
+package makemkv
+
+import "errors"
+
+func ReapOrphans() ([]int, error) {
+	return nil, errors.ErrUnsupported
+}
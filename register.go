@@ -0,0 +1,75 @@
+package makemkv
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"strings"
+)
+
+var ErrKeyRejected = errors.New("makemkv: registration key rejected")
+
+// Register installs key with makemkvcon reg. A rejected key is a
+// FailureError with ErrKeyRejected as its cause, carrying makemkvcon's
+// explanation.
+func Register(key string, opts MkvOptions) error {
+	messages, err := runMessages(opts, "reg", key)
+	if err == nil {
+		return nil
+	}
+	if len(messages) == 0 {
+		return err
+	}
+	return &FailureError{Cause: ErrKeyRejected, Message: messages[len(messages)-1], Err: err}
+}
+
+// CheckKey runs the same quick info as ListDrives and returns a FailureError
+// matching ErrKeyExpired when makemkvcon complains about the key or the
+// evaluation period, for tools that rotate the beta key
+func CheckKey(opts MkvOptions) error {
+	messages, err := runMessages(opts, "info", "disc:9999")
+	var failures failureWatch
+	for _, msg := range messages {
+		failures.observe(msg)
+	}
+	if msg := failures.message(ErrKeyExpired); msg != nil {
+		return &FailureError{Cause: ErrKeyExpired, Message: *msg, Err: err}
+	}
+	// the made up disc failing to open can show up in the exit status
+	if err != nil && len(messages) == 0 {
+		return err
+	}
+	return nil
+}
+
+// runMessages runs makemkvcon and collects the messages it prints
+func runMessages(opts MkvOptions, args ...string) ([]Message, error) {
+	opts, err := opts.withProgress(false)
+	if err != nil {
+		return nil, err
+	}
+	opts, file, cleanup, err := opts.withTransport()
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+	cmd := newCommand(opts, args...)
+
+	var messages []Message
+	var s stopper
+	parseErr, err := runCommand(cmd, &s, file, opts.Audit, func(out io.Reader) error {
+		scanner := bufio.NewScanner(out)
+		for scanner.Scan() {
+			if content, found := strings.CutPrefix(scanner.Text(), "MSG:"); found {
+				if msg, ok := parseMessage(content); ok {
+					messages = append(messages, msg)
+				}
+			}
+		}
+		return scanner.Err()
+	})
+	if err == nil {
+		err = parseErr
+	}
+	return messages, err
+}
@@ -0,0 +1,35 @@
+package makemkv
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegister(t *testing.T) {
+	var audit bytes.Buffer
+	opts := fakeMakemkvcon(t, `MSG:5041,0,0,"Registration key accepted","Registration key accepted"
+`, 0)
+	opts.Audit = NewAuditWriter(&audit)
+	assert.Nil(t, Register("T-key", opts))
+	assert.Contains(t, audit.String(), `"reg","\u003credacted\u003e"`)
+	assert.NotContains(t, audit.String(), "T-key")
+
+	opts = fakeMakemkvcon(t, `MSG:5044,0,0,"Registration key is invalid","Registration key is invalid"
+`, 1)
+	err := Register("T-bad", opts)
+	assert.ErrorIs(t, err, ErrKeyRejected)
+	assert.Equal(t, "makemkv: registration key rejected: Registration key is invalid", err.Error())
+}
+
+func TestCheckKey(t *testing.T) {
+	opts := fakeMakemkvcon(t, `MSG:1005,0,1,"MakeMKV v1.17.6 linux(x64-release) started","%1 started","MakeMKV v1.17.6 linux(x64-release)"
+DRV:0,0,999,1,"BD-RE","","/dev/sr0"
+`, 1)
+	assert.Nil(t, CheckKey(opts))
+
+	opts = fakeMakemkvcon(t, `MSG:5021,260,1,"This application version is too old.  Please download the latest version at http://www.makemkv.com/ or enter a registration key to continue using the current version.","This application version is too old.  Please download the latest version at %1 or enter a registration key to continue using the current version.","http://www.makemkv.com/"
+`, 1)
+	assert.ErrorIs(t, CheckKey(opts), ErrKeyExpired)
+}
@@ -0,0 +1,164 @@
+package makemkv
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+var ErrNotMounted = errors.New("makemkv: remote image is not mounted")
+
+// Mounter makes an ISO kept elsewhere readable as a local file, by copying
+// it, loop or FUSE mounting it, or however else suits where it lives
+type Mounter interface {
+	Mount(url string) (path string, err error)
+	Unmount(path string) error
+}
+
+// RemoteIsoDevice is an ISO at a URL, handed to makemkvcon as an iso: source
+// once Mount has made it local
+type RemoteIsoDevice struct {
+	url     string
+	mounter Mounter
+	mu      sync.Mutex
+	path    string
+}
+
+func NewRemoteIsoDevice(url string, mounter Mounter) *RemoteIsoDevice {
+	return &RemoteIsoDevice{url: url, mounter: mounter}
+}
+
+func (d *RemoteIsoDevice) URL() string {
+	return d.url
+}
+
+// Mount is a no-op when the image is already mounted
+func (d *RemoteIsoDevice) Mount() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.path != "" {
+		return nil
+	}
+	path, err := d.mounter.Mount(d.url)
+	if err != nil {
+		return err
+	}
+	d.path = path
+	return nil
+}
+
+func (d *RemoteIsoDevice) Unmount() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.path == "" {
+		return nil
+	}
+	if err := d.mounter.Unmount(d.path); err != nil {
+		return err
+	}
+	d.path = ""
+	return nil
+}
+
+// Device is the local path, empty until the image is mounted
+func (d *RemoteIsoDevice) Device() string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.path
+}
+
+func (d *RemoteIsoDevice) Type() string {
+	return "iso"
+}
+
+func (d *RemoteIsoDevice) Capabilities() Capabilities {
+	return imageCapabilities
+}
+
+func (d *RemoteIsoDevice) Available() bool {
+	path := d.Device()
+	if path == "" {
+		return false
+	}
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
+
+// CacheMounter downloads http and https images into Dir and keeps them
+// there, so mounting the same URL again doesn't fetch it again. file URLs,
+// like images on an NFS share the system has mounted, are used in place.
+type CacheMounter struct {
+	Dir string
+	// http.DefaultClient when nil
+	Client *http.Client
+}
+
+func (m *CacheMounter) Mount(rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	switch u.Scheme {
+	case "file":
+		return u.Path, nil
+	case "http", "https":
+	default:
+		return "", fmt.Errorf("makemkv: can't mount %s URLs", u.Scheme)
+	}
+
+	sum := sha256.Sum256([]byte(rawURL))
+	path := filepath.Join(m.Dir, hex.EncodeToString(sum[:8])+".iso")
+	if _, err := os.Stat(path); err == nil {
+		return path, nil
+	}
+	if err := m.download(rawURL, path); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// Unmount leaves the cached copy in place for the next Mount
+func (m *CacheMounter) Unmount(path string) error {
+	return nil
+}
+
+// download goes through a temporary file so that an interrupted download
+// is never mistaken for a cached image
+func (m *CacheMounter) download(rawURL string, path string) error {
+	client := m.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Get(rawURL)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("makemkv: fetching %s: %s", rawURL, resp.Status)
+	}
+
+	if err := os.MkdirAll(m.Dir, 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(m.Dir, ".download-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := io.Copy(tmp, resp.Body); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,53 @@
+package makemkv
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRemoteIsoDevice(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path != "/disc.iso" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte("image"))
+	}))
+	defer server.Close()
+
+	mounter := &CacheMounter{Dir: t.TempDir()}
+	device := NewRemoteIsoDevice(server.URL+"/disc.iso", mounter)
+	assert.False(t, device.Available())
+	assert.ErrorIs(t, CheckAccess(device), ErrNotMounted)
+
+	if assert.Nil(t, device.Mount()) {
+		assert.True(t, device.Available())
+		assert.Nil(t, CheckAccess(device))
+		data, err := os.ReadFile(device.Device())
+		assert.Nil(t, err)
+		assert.Equal(t, "image", string(data))
+	}
+	assert.Nil(t, device.Unmount())
+	assert.Equal(t, "", device.Device())
+
+	// the cached copy is reused
+	assert.Nil(t, device.Mount())
+	assert.Equal(t, 1, requests)
+
+	missing := NewRemoteIsoDevice(server.URL+"/missing.iso", mounter)
+	assert.NotNil(t, missing.Mount())
+	entries, _ := os.ReadDir(mounter.Dir)
+	assert.Equal(t, 1, len(entries))
+
+	local := NewRemoteIsoDevice("file:///srv/nas/disc.iso", mounter)
+	assert.Nil(t, local.Mount())
+	assert.Equal(t, "/srv/nas/disc.iso", local.Device())
+
+	assert.NotNil(t, NewRemoteIsoDevice("ftp://nas/disc.iso", mounter).Mount())
+}
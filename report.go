@@ -0,0 +1,123 @@
+package makemkv
+
+import "bytes"
+
+// ParseReport collects oddities in makemkvcon's output that didn't stop it
+// from being parsed, along with how much output there was
+type ParseReport struct {
+	Conflicts []AttrConflict
+	Lines     int
+	// bytes of output, counting line endings
+	Bytes int64
+	// lines whose prefix isn't one makemkvcon is known to print, by prefix
+	UnknownPrefixes map[string]int
+	// lines with a known prefix that couldn't be parsed, and lines with no
+	// prefix at all
+	Malformed int
+	// fields past those the detected release prints, from a newer
+	// makemkvcon, which are ignored
+	ExtraFields int
+}
+
+// linePrefix is the kind of line robot output has, going by its prefix
+type linePrefix int
+
+const (
+	// no colon, so no prefix at all
+	prefixNone linePrefix = iota
+	prefixUnknown
+	prefixMSG
+	prefixDRV
+	prefixTCOUNT
+	prefixCINFO
+	prefixTINFO
+	prefixSINFO
+	prefixPRGT
+	prefixPRGC
+	prefixPRGV
+)
+
+// robot output prefixes, which parsers that only care about some of them
+// still recognise
+var prefixNames = [...][]byte{
+	prefixMSG:    []byte("MSG"),
+	prefixDRV:    []byte("DRV"),
+	prefixTCOUNT: []byte("TCOUNT"),
+	prefixCINFO:  []byte("CINFO"),
+	prefixTINFO:  []byte("TINFO"),
+	prefixSINFO:  []byte("SINFO"),
+	prefixPRGT:   []byte("PRGT"),
+	prefixPRGC:   []byte("PRGC"),
+	prefixPRGV:   []byte("PRGV"),
+}
+
+// parsePrefix tells which kind of line prefix starts, prefixUnknown for
+// anything makemkvcon isn't known to print
+func parsePrefix(prefix []byte) linePrefix {
+	for kind, name := range prefixNames {
+		if name != nil && bytes.Equal(prefix, name) {
+			return linePrefix(kind)
+		}
+	}
+	return prefixUnknown
+}
+
+// add merges in the report of more output
+func (r *ParseReport) add(o ParseReport) {
+	r.Conflicts = append(r.Conflicts, o.Conflicts...)
+	r.Lines += o.Lines
+	r.Bytes += o.Bytes
+	for prefix, n := range o.UnknownPrefixes {
+		if r.UnknownPrefixes == nil {
+			r.UnknownPrefixes = make(map[string]int)
+		}
+		r.UnknownPrefixes[prefix] += n
+	}
+	r.Malformed += o.Malformed
+	r.ExtraFields += o.ExtraFields
+}
+
+// fields checks the number of fields on a line of kind against proto,
+// counting a line that is short as malformed and any extra fields. It
+// reports whether the line has every field.
+func (r *ParseReport) fields(proto protocol, kind linePrefix, n int) bool {
+	want := proto.fields(kind)
+	if n < want {
+		r.Malformed++
+		return false
+	}
+	if want > 0 {
+		r.ExtraFields += n - want
+	}
+	return true
+}
+
+// line counts a line of output of kind, prefix is only looked at for
+// unknown kinds
+func (r *ParseReport) line(kind linePrefix, prefix []byte, length int) {
+	r.Lines++
+	r.Bytes += int64(length) + 1
+	if length == 0 {
+		return
+	}
+	switch kind {
+	case prefixNone:
+		r.Malformed++
+	case prefixUnknown:
+		if r.UnknownPrefixes == nil {
+			r.UnknownPrefixes = make(map[string]int)
+		}
+		r.UnknownPrefixes[string(prefix)]++
+	}
+}
+
+// AttrConflict is an attribute emitted twice for the same entity with
+// different values. TitleId and StreamId are -1 when the attribute belongs
+// to the disc or the title rather than a stream.
+type AttrConflict struct {
+	TitleId  int
+	StreamId int
+	AttrId   int
+	Old      string
+	New      string
+}
@@ -0,0 +1,38 @@
+package makemkv
+
+import (
+	"slices"
+	"time"
+)
+
+// durations makemkvcon reports for the same title can differ by rounding
+const durationSlack = time.Second
+
+// FindTitle returns the id of the title on disc that looks like want, going
+// by segments when want has them and by duration when it doesn't. It gives
+// up when no title or more than one title matches.
+func FindTitle(disc *DiscInfo, want *TitleInfo) (int, bool) {
+	var bySegments, byDuration []int
+	for _, t := range disc.Titles {
+		if !closeDuration(t.Duration, want.Duration) {
+			continue
+		}
+		byDuration = append(byDuration, t.Id)
+		if len(want.Segments) > 0 && slices.Equal(t.Segments, want.Segments) {
+			bySegments = append(bySegments, t.Id)
+		}
+	}
+	switch {
+	case len(bySegments) == 1:
+		return bySegments[0], true
+	case len(want.Segments) == 0 && len(byDuration) == 1:
+		return byDuration[0], true
+	default:
+		return 0, false
+	}
+}
+
+func closeDuration(a, b time.Duration) bool {
+	d := a - b
+	return d <= durationSlack && d >= -durationSlack
+}
@@ -0,0 +1,34 @@
+package makemkv
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindTitle(t *testing.T) {
+	disc := &DiscInfo{Titles: []TitleInfo{
+		{Id: 0, Duration: 2 * time.Hour, Segments: []int{1, 2, 3}},
+		{Id: 1, Duration: 2 * time.Hour, Segments: []int{3, 2, 1}},
+		{Id: 2, Duration: 22 * time.Minute, Segments: []int{7}},
+	}}
+
+	id, ok := FindTitle(disc, &TitleInfo{Duration: 2*time.Hour + time.Second, Segments: []int{3, 2, 1}})
+	assert.True(t, ok)
+	assert.Equal(t, 1, id)
+
+	id, ok = FindTitle(disc, &TitleInfo{Duration: 22 * time.Minute})
+	assert.True(t, ok)
+	assert.Equal(t, 2, id)
+
+	_, ok = FindTitle(disc, &TitleInfo{Duration: 2 * time.Hour})
+	assert.False(t, ok)
+
+	_, ok = FindTitle(disc, &TitleInfo{Duration: time.Hour})
+	assert.False(t, ok)
+
+	// segments that match nothing don't fall back to the duration
+	_, ok = FindTitle(disc, &TitleInfo{Duration: 22 * time.Minute, Segments: []int{8}})
+	assert.False(t, ok)
+}
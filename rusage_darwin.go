@@ -0,0 +1,16 @@
+//go:build darwin || ios
+
+package makemkv
+
+import (
+	"os"
+	"syscall"
+)
+
+func maxRSS(state *os.ProcessState) int64 {
+	if usage, ok := state.SysUsage().(*syscall.Rusage); ok {
+		// reported in bytes
+		return int64(usage.Maxrss)
+	}
+	return 0
+}
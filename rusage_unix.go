@@ -0,0 +1,16 @@
+//go:build unix && !darwin && !ios
+
+package makemkv
+
+import (
+	"os"
+	"syscall"
+)
+
+func maxRSS(state *os.ProcessState) int64 {
+	if usage, ok := state.SysUsage().(*syscall.Rusage); ok {
+		// reported in kilobytes
+		return int64(usage.Maxrss) * 1024
+	}
+	return 0
+}
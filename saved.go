@@ -0,0 +1,100 @@
+package makemkv
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// savedFiles lists the mkv files in dir written since start, which is the
+// only record of saved titles that doesn't come from makemkvcon itself
+func savedFiles(dir string, start time.Time) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var files []string
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.EqualFold(filepath.Ext(entry.Name()), ".mkv") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || info.ModTime().Before(start) {
+			continue
+		}
+		files = append(files, filepath.Join(dir, entry.Name()))
+	}
+	sort.Strings(files)
+	return files
+}
+
+// TitleResult is what became of one title of a rip
+type TitleResult struct {
+	// -1 when a file couldn't be tied to a title
+	TitleId int
+	File    string
+	// of the file on disk, which for failed titles is the partial file they
+	// left behind, zero when they left none
+	Size  int64
+	Saved bool
+}
+
+// makemkvcon's default file names end in the title id, like title_t03.mkv
+var titleSuffix = regexp.MustCompile(`_t(\d+)\.mkv$`)
+
+// titleResults ties saved files to titles by the file names in scanned, by
+// makemkvcon's default naming, or by the one title the job asked for, and
+// adds the titles makemkvcon said it failed to save, keeping any partial file
+// they left behind. titleId is -1 for jobs ripping every title.
+func titleResults(files []string, failed map[int]string, scanned *DiscInfo, titleId int) []TitleResult {
+	byName := make(map[string]int)
+	if scanned != nil {
+		for _, title := range scanned.Titles {
+			if title.FileName != "" {
+				byName[title.FileName] = title.Id
+			}
+		}
+	}
+
+	failedNames := make(map[string]int, len(failed))
+	for id, file := range failed {
+		failedNames[filepath.Base(file)] = id
+	}
+
+	var results []TitleResult
+	partial := make(map[int]bool)
+	for _, file := range files {
+		result := TitleResult{TitleId: -1, File: file, Saved: true}
+		name := filepath.Base(file)
+		if id, ok := failedNames[name]; ok {
+			result.TitleId, result.Saved = id, false
+			partial[id] = true
+		} else if id, ok := byName[name]; ok {
+			result.TitleId = id
+		} else if m := titleSuffix.FindStringSubmatch(strings.ToLower(name)); m != nil {
+			result.TitleId, _ = strconv.Atoi(m[1])
+		} else if titleId >= 0 && len(files) == 1 {
+			result.TitleId = titleId
+		}
+		if info, err := os.Stat(file); err == nil {
+			result.Size = info.Size()
+		}
+		results = append(results, result)
+	}
+	for id, file := range failed {
+		if !partial[id] {
+			results = append(results, TitleResult{TitleId: id, File: file})
+		}
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].TitleId != results[j].TitleId {
+			return results[i].TitleId < results[j].TitleId
+		}
+		return results[i].File < results[j].File
+	})
+	return results
+}
@@ -0,0 +1,54 @@
+package makemkv
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSavedFiles(t *testing.T) {
+	dir := t.TempDir()
+	old := filepath.Join(dir, "old_t00.mkv")
+	assert.Nil(t, os.WriteFile(old, nil, 0o644))
+	past := time.Now().Add(-time.Hour)
+	assert.Nil(t, os.Chtimes(old, past, past))
+
+	start := time.Now().Add(-time.Minute)
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "title_t01.mkv"), nil, 0o644))
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "title_t00.MKV"), nil, 0o644))
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "notes.txt"), nil, 0o644))
+
+	assert.Equal(t, []string{
+		filepath.Join(dir, "title_t00.MKV"),
+		filepath.Join(dir, "title_t01.mkv"),
+	}, savedFiles(dir, start))
+	assert.Nil(t, savedFiles(filepath.Join(dir, "missing"), start))
+}
+
+func TestTitleResults(t *testing.T) {
+	dir := t.TempDir()
+	named := filepath.Join(dir, "Movie.mkv")
+	assert.Nil(t, os.WriteFile(named, []byte("12345"), 0o644))
+	byDefault := filepath.Join(dir, "title_t03.mkv")
+	assert.Nil(t, os.WriteFile(byDefault, []byte("123"), 0o644))
+	unknown := filepath.Join(dir, "extra.mkv")
+	assert.Nil(t, os.WriteFile(unknown, nil, 0o644))
+	partial := filepath.Join(dir, "title_t05.mkv")
+	assert.Nil(t, os.WriteFile(partial, []byte("1"), 0o644))
+
+	scanned := &DiscInfo{Titles: []TitleInfo{{Id: 1, FileName: "Movie.mkv"}}}
+	failed := map[int]string{4: filepath.Join(dir, "title_t04.mkv"), 5: partial}
+	assert.Equal(t, []TitleResult{
+		{TitleId: -1, File: unknown, Saved: true},
+		{TitleId: 1, File: named, Size: 5, Saved: true},
+		{TitleId: 3, File: byDefault, Size: 3, Saved: true},
+		{TitleId: 4, File: filepath.Join(dir, "title_t04.mkv")},
+		{TitleId: 5, File: partial, Size: 1},
+	}, titleResults([]string{named, byDefault, unknown, partial}, failed, scanned, -1))
+
+	assert.Equal(t, []TitleResult{{TitleId: 2, File: unknown, Saved: true}},
+		titleResults([]string{unknown}, nil, nil, 2))
+}
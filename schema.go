@@ -0,0 +1,66 @@
+package makemkv
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// DiscInfoSchema is the version of the document MarshalDiscInfo writes. It
+// goes up whenever a change to DiscInfo needs older documents migrated.
+const DiscInfoSchema = 1
+
+var ErrUnknownSchema = errors.New("makemkv: unknown disc info schema")
+
+type discInfoDocument struct {
+	Schema int       `json:"schema"`
+	Disc   *DiscInfo `json:"disc"`
+}
+
+// discInfoMigrations[n] turns a schema n document into a schema n+1 one
+var discInfoMigrations = [DiscInfoSchema]func(map[string]json.RawMessage) (map[string]json.RawMessage, error){
+	// before schemas, a document was a DiscInfo on its own
+	0: func(doc map[string]json.RawMessage) (map[string]json.RawMessage, error) {
+		disc, err := json.Marshal(doc)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]json.RawMessage{"schema": json.RawMessage("1"), "disc": disc}, nil
+	},
+}
+
+// MarshalDiscInfo encodes disc along with the schema it was written in, so
+// that later versions of the package can still load it
+func MarshalDiscInfo(disc *DiscInfo) ([]byte, error) {
+	return json.Marshal(discInfoDocument{Schema: DiscInfoSchema, Disc: disc})
+}
+
+// UnmarshalDiscInfo decodes a document written by any version of
+// MarshalDiscInfo, or a DiscInfo encoded directly as JSON, migrating it to
+// the current schema first
+func UnmarshalDiscInfo(data []byte) (*DiscInfo, error) {
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	schema := 0
+	if raw, ok := doc["schema"]; ok {
+		if err := json.Unmarshal(raw, &schema); err != nil {
+			return nil, err
+		}
+	}
+	if schema < 0 || schema > DiscInfoSchema {
+		return nil, fmt.Errorf("%w: %d", ErrUnknownSchema, schema)
+	}
+	for ; schema < DiscInfoSchema; schema++ {
+		var err error
+		if doc, err = discInfoMigrations[schema](doc); err != nil {
+			return nil, fmt.Errorf("makemkv: migrating disc info from schema %d: %w", schema, err)
+		}
+	}
+	var disc DiscInfo
+	if err := json.Unmarshal(doc["disc"], &disc); err != nil {
+		return nil, err
+	}
+	return &disc, nil
+}
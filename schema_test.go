@@ -0,0 +1,34 @@
+package makemkv
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiscInfoSchema(t *testing.T) {
+	disc := &DiscInfo{
+		Name:     "Movie",
+		Titles:   []TitleInfo{{Id: 0, Duration: time.Hour, Segments: []int{1, 2}}},
+		RawAttrs: map[int]string{ap_iaName: "Movie"},
+		Version:  Version{Major: 1, Minor: 17, Patch: 6},
+	}
+	data, err := MarshalDiscInfo(disc)
+	assert.Nil(t, err)
+	assert.Contains(t, string(data), `"schema":1`)
+	decoded, err := UnmarshalDiscInfo(data)
+	assert.Nil(t, err)
+	assert.Equal(t, disc, decoded)
+
+	// written before there were schemas
+	bare, err := json.Marshal(disc)
+	assert.Nil(t, err)
+	decoded, err = UnmarshalDiscInfo(bare)
+	assert.Nil(t, err)
+	assert.Equal(t, disc, decoded)
+
+	_, err = UnmarshalDiscInfo([]byte(`{"schema":99,"disc":{}}`))
+	assert.ErrorIs(t, err, ErrUnknownSchema)
+}
@@ -0,0 +1,21 @@
+package makemkv
+
+import "strings"
+
+// MkvDefault reports whether makemkvcon will set the default track flag on
+// the stream in the output mkv, marked by a 'd' in its mkv flags. It says
+// nothing about whether MakeMKV selects the stream for ripping, robot output
+// doesn't carry the selection.
+func (v *VideoStreamInfo) MkvDefault() bool {
+	return strings.ContainsRune(v.MkvFlags, 'd')
+}
+
+// MkvDefault is VideoStreamInfo.MkvDefault for audio streams
+func (a *AudioStreamInfo) MkvDefault() bool {
+	return strings.ContainsRune(a.MkvFlags, 'd')
+}
+
+// MkvDefault is VideoStreamInfo.MkvDefault for subtitle streams
+func (s *SubtitleStreamInfo) MkvDefault() bool {
+	return strings.ContainsRune(s.MkvFlags, 'd')
+}
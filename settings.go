@@ -0,0 +1,156 @@
+package makemkv
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// keys MakeMKV reads from settings.conf, there are many more
+const (
+	SettingKey              = "app_Key"
+	SettingDefaultSelection = "app_DefaultSelectionString"
+	SettingDestinationDir   = "app_DestinationDir"
+	SettingDataDir          = "app_DataDir"
+	SettingExpertMode       = "app_ExpertMode"
+	SettingMinimumLength    = "dvd_MinimumTitleLength"
+	SettingErrorRetryCount  = "io_ErrorRetryCount"
+	SettingReadBufferSize   = "io_RBufSizeMB"
+)
+
+// Settings is MakeMKV's settings.conf. Comments and the order of keys are
+// kept when it is written back.
+type Settings struct {
+	lines []settingsLine
+}
+
+// a key of "" is a comment or blank line kept as raw
+type settingsLine struct {
+	key   string
+	value string
+	raw   string
+}
+
+// DefaultSettingsPath is where MakeMKV keeps its settings on Linux. Other
+// platforms keep them in the registry or a plist instead.
+func DefaultSettingsPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".MakeMKV", "settings.conf"), nil
+}
+
+// LoadSettings reads the settings at path, which don't have to exist yet
+func LoadSettings(path string) (*Settings, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return &Settings{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ParseSettings(f)
+}
+
+func ParseSettings(r io.Reader) (*Settings, error) {
+	var s Settings
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		key, value, found := strings.Cut(trimmed, "=")
+		if !found || strings.HasPrefix(trimmed, "#") {
+			s.lines = append(s.lines, settingsLine{raw: line})
+			continue
+		}
+		key = strings.TrimSpace(key)
+		value = string(unquote([]byte(strings.TrimSpace(value))))
+		s.lines = append(s.lines, settingsLine{key: key, value: value})
+	}
+	return &s, scanner.Err()
+}
+
+func (s *Settings) Get(key string) (string, bool) {
+	for _, line := range s.lines {
+		if line.key == key {
+			return line.value, true
+		}
+	}
+	return "", false
+}
+
+// Set replaces the key's value in place, or adds it at the end
+func (s *Settings) Set(key string, value string) {
+	for i := range s.lines {
+		if s.lines[i].key == key {
+			s.lines[i].value = value
+			return
+		}
+	}
+	s.lines = append(s.lines, settingsLine{key: key, value: value})
+}
+
+func (s *Settings) Delete(key string) {
+	lines := s.lines[:0]
+	for _, line := range s.lines {
+		if line.key != key {
+			lines = append(lines, line)
+		}
+	}
+	s.lines = lines
+}
+
+// Keys returns the keys in file order
+func (s *Settings) Keys() []string {
+	var keys []string
+	for _, line := range s.lines {
+		if line.key != "" {
+			keys = append(keys, line.key)
+		}
+	}
+	return keys
+}
+
+func (s *Settings) Write(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	for _, line := range s.lines {
+		if line.key == "" {
+			bw.WriteString(line.raw)
+		} else {
+			bw.WriteString(line.key + " = " + quoteSetting(line.value))
+		}
+		bw.WriteByte('\n')
+	}
+	return bw.Flush()
+}
+
+// Save writes the settings to path through a temporary file, so MakeMKV
+// never reads a half written file
+func (s *Settings) Save(path string) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, ".settings-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if err := s.Write(tmp); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func quoteSetting(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `"`, `\"`)
+	return `"` + value + `"`
+}
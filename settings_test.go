@@ -0,0 +1,69 @@
+package makemkv
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const settingsConf = `#
+# MakeMKV settings file, written by MakeMKV v1.17.6 linux(x64-release)
+#
+
+app_DefaultSelectionString = "-sel:all,+sel:(favlang|nolang|single),-sel:(havemulti|havecore),-sel:mvcvideo,=100:all,-10:favlang"
+app_DestinationDir = "/home/user/Videos"
+dvd_MinimumTitleLength = "120"
+`
+
+func TestSettings(t *testing.T) {
+	settings, err := ParseSettings(strings.NewReader(settingsConf))
+	assert.Nil(t, err)
+	assert.Equal(t, []string{SettingDefaultSelection, SettingDestinationDir, SettingMinimumLength}, settings.Keys())
+	if value, ok := settings.Get(SettingMinimumLength); assert.True(t, ok) {
+		assert.Equal(t, "120", value)
+	}
+
+	settings.Set(SettingMinimumLength, "300")
+	settings.Set(SettingKey, "T-key")
+	settings.Set(SettingDestinationDir, `/mnt/rips/"new"`)
+	settings.Delete(SettingDefaultSelection)
+	_, ok := settings.Get(SettingDefaultSelection)
+	assert.False(t, ok)
+
+	var buf bytes.Buffer
+	assert.Nil(t, settings.Write(&buf))
+	assert.Equal(t, `#
+# MakeMKV settings file, written by MakeMKV v1.17.6 linux(x64-release)
+#
+
+app_DestinationDir = "/mnt/rips/\"new\""
+dvd_MinimumTitleLength = "300"
+app_Key = "T-key"
+`, buf.String())
+
+	reread, err := ParseSettings(&buf)
+	assert.Nil(t, err)
+	value, _ := reread.Get(SettingDestinationDir)
+	assert.Equal(t, `/mnt/rips/"new"`, value)
+}
+
+func TestSettingsSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".MakeMKV", "settings.conf")
+	settings, err := LoadSettings(path)
+	assert.Nil(t, err)
+	assert.Nil(t, settings.Keys())
+
+	settings.Set(SettingErrorRetryCount, "5")
+	assert.Nil(t, settings.Save(path))
+	data, err := os.ReadFile(path)
+	assert.Nil(t, err)
+	assert.Equal(t, "io_ErrorRetryCount = \"5\"\n", string(data))
+
+	loaded, err := LoadSettings(path)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{SettingErrorRetryCount}, loaded.Keys())
+}
@@ -0,0 +1,103 @@
+package makemkv
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrBadSignature = errors.New("makemkv: malformed title signature")
+
+const signaturePrefix = "mkv1"
+
+// sizes within the same 256MiB are one bucket, enough to tell a feature from
+// a shorter cut without caring about small differences between releases
+const sizeBucketShift = 28
+
+// TitleSignature is a compact description of a title for sharing which
+// playlist is the right one, as its String form
+type TitleSignature struct {
+	// whole seconds
+	Duration time.Duration
+	Chapters int
+	// a hash of the segment map, zero when makemkvcon reported none
+	Segments   uint32
+	SizeBucket int64
+}
+
+func Signature(title TitleInfo) TitleSignature {
+	sig := TitleSignature{
+		Duration:   title.Duration.Truncate(time.Second),
+		Chapters:   title.ChapterCount,
+		SizeBucket: title.FileSize >> sizeBucketShift,
+	}
+	if len(title.Segments) > 0 {
+		h := fnv.New32a()
+		for i, segment := range title.Segments {
+			if i > 0 {
+				h.Write([]byte(","))
+			}
+			h.Write([]byte(strconv.Itoa(segment)))
+		}
+		sig.Segments = h.Sum32()
+	}
+	return sig
+}
+
+// String gives the canonical form, like "mkv1:5551:42:21cdd3d7:160"
+func (s TitleSignature) String() string {
+	return fmt.Sprintf("%s:%d:%d:%08x:%d", signaturePrefix, int64(s.Duration/time.Second), s.Chapters, s.Segments, s.SizeBucket)
+}
+
+func ParseSignature(s string) (TitleSignature, error) {
+	fields := strings.Split(s, ":")
+	if len(fields) != 5 || fields[0] != signaturePrefix {
+		return TitleSignature{}, fmt.Errorf("%w: %q", ErrBadSignature, s)
+	}
+	seconds, err1 := strconv.ParseInt(fields[1], 10, 64)
+	chapters, err2 := strconv.Atoi(fields[2])
+	segments, err3 := strconv.ParseUint(fields[3], 16, 32)
+	bucket, err4 := strconv.ParseInt(fields[4], 10, 64)
+	if err := errors.Join(err1, err2, err3, err4); err != nil {
+		return TitleSignature{}, fmt.Errorf("%w: %q", ErrBadSignature, s)
+	}
+	return TitleSignature{
+		Duration:   time.Duration(seconds) * time.Second,
+		Chapters:   chapters,
+		Segments:   uint32(segments),
+		SizeBucket: bucket,
+	}, nil
+}
+
+// Matches allows for the rounding and size differences seen between scans
+// and releases of the same title. Segment maps are compared only when both
+// signatures have one.
+func (s TitleSignature) Matches(other TitleSignature) bool {
+	if !closeDuration(s.Duration, other.Duration) || s.Chapters != other.Chapters {
+		return false
+	}
+	if s.Segments != 0 && other.Segments != 0 && s.Segments != other.Segments {
+		return false
+	}
+	d := s.SizeBucket - other.SizeBucket
+	return d <= 1 && d >= -1
+}
+
+// MatchSignatures returns the ids of the disc's titles matching any of the
+// signatures, in disc order
+func MatchSignatures(disc *DiscInfo, signatures []TitleSignature) []int {
+	var ids []int
+	for _, title := range disc.Titles {
+		sig := Signature(title)
+		for _, want := range signatures {
+			if sig.Matches(want) {
+				ids = append(ids, title.Id)
+				break
+			}
+		}
+	}
+	return ids
+}
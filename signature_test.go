@@ -0,0 +1,42 @@
+package makemkv
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignature(t *testing.T) {
+	title := TitleInfo{
+		Id:           0,
+		Duration:     time.Hour + 32*time.Minute + 31*time.Second + 400*time.Millisecond,
+		ChapterCount: 42,
+		FileSize:     40 << 30,
+		Segments:     []int{1, 2, 3},
+	}
+	sig := Signature(title)
+	assert.Equal(t, "mkv1:5551:42:21cdd3d7:160", sig.String())
+
+	parsed, err := ParseSignature(sig.String())
+	assert.Nil(t, err)
+	assert.Equal(t, sig, parsed)
+	_, err = ParseSignature("mkv1:5551:42:zz:160")
+	assert.ErrorIs(t, err, ErrBadSignature)
+	_, err = ParseSignature("mkv2:5551:42:0:160")
+	assert.ErrorIs(t, err, ErrBadSignature)
+
+	rescanned := title
+	rescanned.Duration += time.Second
+	rescanned.FileSize += 100 << 20
+	assert.True(t, Signature(rescanned).Matches(sig))
+	reordered := title
+	reordered.Segments = []int{3, 2, 1}
+	assert.False(t, Signature(reordered).Matches(sig))
+	reordered.Segments = nil
+	assert.True(t, Signature(reordered).Matches(sig))
+
+	disc := &DiscInfo{Titles: []TitleInfo{{Id: 0, Duration: time.Hour, ChapterCount: 10}, reordered, title}}
+	disc.Titles[1].Id, disc.Titles[2].Id = 1, 2
+	assert.Equal(t, []int{1, 2}, MatchSignatures(disc, []TitleSignature{sig}))
+}
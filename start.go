@@ -0,0 +1,63 @@
+package makemkv
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+type StartTimeoutError struct {
+	Timeout time.Duration
+}
+
+func (e *StartTimeoutError) Error() string {
+	return fmt.Sprintf("makemkv: no output within %s of starting", e.Timeout)
+}
+
+var bannerPrefix = []byte(strconv.Itoa(msgStarted) + ",")
+
+// startWatch stops a job with StopStalled when makemkvcon prints nothing
+// past its startup banner in time, which is how it hangs on an open tray or
+// a drive it can't access
+type startWatch struct {
+	mu    sync.Mutex
+	timer *time.Timer
+	// only touched by the goroutine reading output
+	seen bool
+}
+
+// newStartWatch returns nil when there is no timeout, which is safe to use
+func newStartWatch(timeout time.Duration, stop func(StopReason, error)) *startWatch {
+	if timeout <= 0 {
+		return nil
+	}
+	return &startWatch{timer: time.AfterFunc(timeout, func() {
+		stop(StopStalled, &StartTimeoutError{Timeout: timeout})
+	})}
+}
+
+func (w *startWatch) observe(prefix []byte, content []byte) {
+	w.output(bytes.Equal(prefix, []byte("MSG")) && bytes.HasPrefix(content, bannerPrefix))
+}
+
+func (w *startWatch) output(banner bool) {
+	if w == nil || banner || w.seen {
+		return
+	}
+	w.seen = true
+	w.close()
+}
+
+func (w *startWatch) close() {
+	if w == nil {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.timer != nil {
+		w.timer.Stop()
+		w.timer = nil
+	}
+}
@@ -0,0 +1,38 @@
+package makemkv
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStartWatch(t *testing.T) {
+	stopped := make(chan error, 1)
+	stop := func(reason StopReason, err error) {
+		assert.Equal(t, StopStalled, reason)
+		stopped <- err
+	}
+
+	w := newStartWatch(20*time.Millisecond, stop)
+	w.observe([]byte("MSG"), []byte(`1005,0,1,"MakeMKV v1.17.6 linux(x64-release) started"`))
+	select {
+	case err := <-stopped:
+		assert.Equal(t, &StartTimeoutError{Timeout: 20 * time.Millisecond}, err)
+	case <-time.After(time.Second):
+		t.Fatal("banner alone should not count as output")
+	}
+
+	w = newStartWatch(20*time.Millisecond, stop)
+	w.observe([]byte("PRGT"), []byte(`5018,0,"Opening disc"`))
+	select {
+	case <-stopped:
+		t.Fatal("stopped after output arrived")
+	case <-time.After(60 * time.Millisecond):
+	}
+	w.close()
+
+	var none *startWatch = newStartWatch(0, stop)
+	none.observe([]byte("PRGT"), nil)
+	none.close()
+}
@@ -0,0 +1,45 @@
+package makemkv
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// StatusEncoder writes statuses as newline delimited JSON, one object per
+// line, for consumers that are not written in Go.
+type StatusEncoder struct {
+	enc *json.Encoder
+}
+
+func NewStatusEncoder(w io.Writer) *StatusEncoder {
+	return &StatusEncoder{enc: json.NewEncoder(w)}
+}
+
+func (e *StatusEncoder) Encode(status Status) error {
+	return e.enc.Encode(status)
+}
+
+// EncodeAll encodes every status received on statuschan until it is closed.
+func (e *StatusEncoder) EncodeAll(statuschan <-chan Status) error {
+	for status := range statuschan {
+		if err := e.Encode(status); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type StatusDecoder struct {
+	dec *json.Decoder
+}
+
+func NewStatusDecoder(r io.Reader) *StatusDecoder {
+	return &StatusDecoder{dec: json.NewDecoder(r)}
+}
+
+// Decode returns the next status, or io.EOF once the input is exhausted.
+func (d *StatusDecoder) Decode() (Status, error) {
+	var status Status
+	err := d.dec.Decode(&status)
+	return status, err
+}
@@ -0,0 +1,34 @@
+package makemkv
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatusEncoderRoundTrip(t *testing.T) {
+	statuses := []Status{
+		{Title: "Saving to MKV file", Channel: "Analyzing seamless segments", Current: 0, Total: 0, Max: 65536},
+		{Title: "Saving to MKV file", Channel: "Saving to MKV file", Current: 1024, Total: 512, Max: 65536},
+	}
+	statuschan := make(chan Status, len(statuses))
+	for _, status := range statuses {
+		statuschan <- status
+	}
+	close(statuschan)
+
+	var buf bytes.Buffer
+	assert.Nil(t, NewStatusEncoder(&buf).EncodeAll(statuschan))
+	assert.Equal(t, 2, bytes.Count(buf.Bytes(), []byte("\n")))
+
+	dec := NewStatusDecoder(&buf)
+	for _, expected := range statuses {
+		status, err := dec.Decode()
+		assert.Nil(t, err)
+		assert.Equal(t, expected, status)
+	}
+	_, err := dec.Decode()
+	assert.Equal(t, io.EOF, err)
+}
@@ -0,0 +1,168 @@
+package makemkv
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"sync"
+)
+
+type StopReason int
+
+const (
+	StopNone StopReason = iota
+	StopCanceled
+	StopTimeout
+	StopStalled
+	StopDiskSpace
+	StopShutdown
+	StopThreshold
+)
+
+func (r StopReason) String() string {
+	switch r {
+	case StopNone:
+		return "none"
+	case StopCanceled:
+		return "canceled"
+	case StopTimeout:
+		return "timeout"
+	case StopStalled:
+		return "stalled"
+	case StopDiskSpace:
+		return "disk space"
+	case StopShutdown:
+		return "shutdown"
+	case StopThreshold:
+		return "threshold exceeded"
+	default:
+		return "unknown"
+	}
+}
+
+// StoppedError is returned by Run when a job was stopped on purpose rather
+// than failing on its own. Err holds whatever the process exited with.
+type StoppedError struct {
+	Reason StopReason
+	Err    error
+}
+
+func (e *StoppedError) Error() string {
+	return "makemkv: job stopped: " + e.Reason.String()
+}
+
+func (e *StoppedError) Unwrap() error {
+	return e.Err
+}
+
+type stopper struct {
+	mu     sync.Mutex
+	cmd    *exec.Cmd
+	reason StopReason
+	cause  error
+	// the context of the RunContext in progress, if any
+	ctx context.Context
+}
+
+// watch stops the running command when ctx is done, until the returned
+// func is called. A ctx that ends between runs only affects runs started
+// while it is still being watched.
+func (s *stopper) watch(ctx context.Context) func() {
+	if ctx.Done() == nil {
+		return func() {}
+	}
+	s.mu.Lock()
+	s.ctx = ctx
+	s.mu.Unlock()
+	done := make(chan struct{})
+	exited := make(chan struct{})
+	go func() {
+		defer close(exited)
+		select {
+		case <-ctx.Done():
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			if s.cmd != nil && s.reason == StopNone {
+				s.reason, s.cause = contextReason(ctx.Err()), ctx.Err()
+				killProcessGroup(s.cmd)
+			}
+		case <-done:
+		}
+	}()
+	return func() {
+		close(done)
+		<-exited
+		s.mu.Lock()
+		s.ctx = nil
+		s.mu.Unlock()
+	}
+}
+
+func contextReason(err error) StopReason {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return StopTimeout
+	}
+	return StopCanceled
+}
+
+func (s *stopper) start(cmd *exec.Cmd) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.reason != StopNone {
+		err := &StoppedError{Reason: s.reason, Err: s.cause}
+		s.reason = StopNone
+		s.cause = nil
+		return err
+	}
+	if s.ctx != nil && s.ctx.Err() != nil {
+		return &StoppedError{Reason: contextReason(s.ctx.Err()), Err: s.ctx.Err()}
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	s.cmd = cmd
+	return nil
+}
+
+func (s *stopper) stop(reason StopReason) {
+	s.stopCause(reason, nil)
+}
+
+// stopCause is stop with a more specific error to report in place of the one
+// the killed process exits with
+func (s *stopper) stopCause(reason StopReason, cause error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// the first reason wins, a timeout followed by a shutdown is still a timeout
+	if s.reason == StopNone {
+		s.reason = reason
+		s.cause = cause
+	}
+	if s.cmd != nil {
+		killProcessGroup(s.cmd)
+	}
+}
+
+// exited forgets the command once it has exited, stopping it is then a
+// matter of the reason alone
+func (s *stopper) exited() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cmd = nil
+}
+
+func (s *stopper) finish(err error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	reason, cause := s.reason, s.cause
+	s.cmd = nil
+	s.reason = StopNone
+	s.cause = nil
+	if reason != StopNone {
+		if cause != nil {
+			err = cause
+		}
+		return &StoppedError{Reason: reason, Err: err}
+	}
+	return err
+}
@@ -0,0 +1,134 @@
+//go:build unix
+
+package makemkv
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/aravance/go-makemkv/makemkvtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func runSleep(s *stopper) error {
+	cmd := exec.Command("sleep", "10")
+	setProcessGroup(cmd)
+	if err := s.start(cmd); err != nil {
+		return s.finish(err)
+	}
+	return s.finish(cmd.Wait())
+}
+
+func TestStopperWatchContext(t *testing.T) {
+	var s stopper
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	release := s.watch(ctx)
+
+	start := time.Now()
+	err := runSleep(&s)
+	assert.Less(t, time.Since(start), 5*time.Second)
+	var stopped *StoppedError
+	if assert.True(t, errors.As(err, &stopped)) {
+		assert.Equal(t, StopTimeout, stopped.Reason)
+	}
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// still watched, so the next run doesn't start at all
+	err = runSleep(&s)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	release()
+
+	ctx, cancel = context.WithCancel(context.Background())
+	release = s.watch(ctx)
+	cancel()
+	release()
+	// a context that ended before being released leaves no stop behind
+	cmd := exec.Command("true")
+	assert.Nil(t, s.start(cmd))
+	assert.Nil(t, s.finish(cmd.Wait()))
+}
+
+func TestFakeMkvStopped(t *testing.T) {
+	opts := fakeMakemkvcon(t, `PRGT:5018,0,"Saving to MKV file"
+PRGV:100,200,65536
+`, 0)
+	opts.Env = append(opts.Env, makemkvtest.Hold(time.Minute))
+	job := Mkv(NewIsoDevice("/disc.iso"), 0, t.TempDir(), opts)
+	job.Statuschan = make(chan Status, 10)
+	go func() {
+		<-job.Statuschan
+		job.Stop(StopShutdown)
+	}()
+
+	start := time.Now()
+	_, err := job.RunContext(context.Background())
+	assert.Less(t, time.Since(start), 30*time.Second)
+	var stopped *StoppedError
+	if assert.True(t, errors.As(err, &stopped)) {
+		assert.Equal(t, StopShutdown, stopped.Reason)
+	}
+	assert.Equal(t, Status{Title: "Saving to MKV file", TitleCode: 5018, Current: 100, Total: 200, Max: 65536, Seq: 2, SavingTitle: "0", Final: true, Stopped: StopShutdown}, <-job.Statuschan)
+}
+
+func TestFakeMkvCancel(t *testing.T) {
+	const output = `PRGT:5018,0,"Saving to MKV file"
+PRGV:100,200,65536
+`
+	var audit bytes.Buffer
+	opts := fakeMakemkvcon(t, output, 0)
+	opts.Env = append(opts.Env, makemkvtest.Hold(time.Minute))
+	opts.Audit = NewAuditWriter(&audit)
+
+	// canceled before start, makemkvcon is never run
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := Mkv(NewIsoDevice("/disc.iso"), 0, t.TempDir(), opts).RunContext(ctx)
+	var stopped *StoppedError
+	if assert.True(t, errors.As(err, &stopped)) {
+		assert.Equal(t, StopCanceled, stopped.Reason)
+	}
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Contains(t, audit.String(), `"exit_code":-1`)
+
+	// canceled mid-rip
+	job := Mkv(NewIsoDevice("/disc.iso"), 0, t.TempDir(), opts)
+	job.Statuschan = make(chan Status, 10)
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-job.Statuschan
+		cancel()
+	}()
+	start := time.Now()
+	_, err = job.RunContext(ctx)
+	assert.Less(t, time.Since(start), 30*time.Second)
+	if assert.True(t, errors.As(err, &stopped)) {
+		assert.Equal(t, StopCanceled, stopped.Reason)
+	}
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, StopCanceled, (<-job.Statuschan).Stopped)
+}
+
+func TestFakeMkvStopAfterExit(t *testing.T) {
+	opts := fakeMakemkvcon(t, `MSG:5036,0,2,"Copy complete. 1 titles saved.","Copy complete. %1 titles saved.","1"
+`, 0)
+	job := Mkv(NewIsoDevice("/disc.iso"), 0, t.TempDir(), opts)
+	result, err := job.RunContext(context.Background())
+	assert.Nil(t, err)
+	job.Stop(StopShutdown)
+	assert.Equal(t, OutcomeSuccess, result.Outcome)
+
+	// the stop carries over to the next run instead
+	_, err = job.RunContext(context.Background())
+	var stopped *StoppedError
+	if assert.True(t, errors.As(err, &stopped)) {
+		assert.Equal(t, StopShutdown, stopped.Reason)
+	}
+	_, err = job.RunContext(context.Background())
+	assert.Nil(t, err)
+}
@@ -0,0 +1,91 @@
+package makemkv
+
+import "sort"
+
+type StreamKind int
+
+const (
+	StreamUnknown StreamKind = iota
+	StreamVideo
+	StreamAudio
+	StreamSubtitle
+)
+
+func (k StreamKind) String() string {
+	switch k {
+	case StreamVideo:
+		return "video"
+	case StreamAudio:
+		return "audio"
+	case StreamSubtitle:
+		return "subtitle"
+	default:
+		return "unknown"
+	}
+}
+
+// Stream is what the three stream types have in common, for code that
+// doesn't care which kind it is looking at
+type Stream interface {
+	StreamId() int
+	Kind() StreamKind
+	// empty for video streams, which carry no language of their own
+	Language() string
+	Codec() string
+	Flags() int
+	Attr(id int) (string, bool)
+	// the ids of every attribute captured for the stream, ascending
+	AttrIds() []int
+}
+
+func (v *VideoStreamInfo) StreamId() int    { return v.Id }
+func (v *VideoStreamInfo) Kind() StreamKind { return StreamVideo }
+func (v *VideoStreamInfo) Language() string { return "" }
+func (v *VideoStreamInfo) Codec() string    { return v.CodecShort }
+func (v *VideoStreamInfo) Flags() int       { return v.StreamFlags }
+func (v *VideoStreamInfo) Attr(id int) (string, bool) {
+	value, ok := v.RawAttrs[id]
+	return value, ok
+}
+func (v *VideoStreamInfo) AttrIds() []int { return attrIds(v.RawAttrs) }
+
+func (a *AudioStreamInfo) StreamId() int    { return a.Id }
+func (a *AudioStreamInfo) Kind() StreamKind { return StreamAudio }
+func (a *AudioStreamInfo) Language() string { return a.LangCode }
+func (a *AudioStreamInfo) Codec() string    { return a.CodecShort }
+func (a *AudioStreamInfo) Flags() int       { return a.StreamFlags }
+func (a *AudioStreamInfo) Attr(id int) (string, bool) {
+	value, ok := a.RawAttrs[id]
+	return value, ok
+}
+func (a *AudioStreamInfo) AttrIds() []int { return attrIds(a.RawAttrs) }
+
+func (s *SubtitleStreamInfo) StreamId() int    { return s.Id }
+func (s *SubtitleStreamInfo) Kind() StreamKind { return StreamSubtitle }
+func (s *SubtitleStreamInfo) Language() string { return s.LangCode }
+func (s *SubtitleStreamInfo) Codec() string    { return s.CodecShort }
+func (s *SubtitleStreamInfo) Flags() int       { return s.StreamFlags }
+func (s *SubtitleStreamInfo) Attr(id int) (string, bool) {
+	value, ok := s.RawAttrs[id]
+	return value, ok
+}
+func (s *SubtitleStreamInfo) AttrIds() []int { return attrIds(s.RawAttrs) }
+
+// Streams returns all of the title's streams in makemkvcon's order. They
+// point into the title, so changes through them are seen on the title.
+func (t *TitleInfo) Streams() []Stream {
+	streams := make([]Stream, 0, len(t.VideoStreams)+len(t.AudioStreams)+len(t.SubtitleStreams))
+	for i := range t.VideoStreams {
+		streams = append(streams, &t.VideoStreams[i])
+	}
+	for i := range t.AudioStreams {
+		streams = append(streams, &t.AudioStreams[i])
+	}
+	for i := range t.SubtitleStreams {
+		streams = append(streams, &t.SubtitleStreams[i])
+	}
+	sort.SliceStable(streams, func(a, b int) bool {
+		return streams[a].StreamId() < streams[b].StreamId()
+	})
+	return streams
+}
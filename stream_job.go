@@ -0,0 +1,143 @@
+package makemkv
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// StreamJob serves a disc over http with makemkvcon stream until stopped
+type StreamJob struct {
+	// advertise the server over UPnP
+	Upnp *bool
+	// address and port to listen on, makemkvcon picks when unset
+	BindIp   string
+	BindPort *int
+
+	device  Device
+	options MkvOptions
+	stopper stopper
+
+	once  sync.Once
+	ready chan struct{}
+	mu    sync.Mutex
+	url   string
+}
+
+// Serve is the constructor for makemkvcon stream, named so as not to clash
+// with the Stream interface
+func Serve(device Device, opts MkvOptions) *StreamJob {
+	return &StreamJob{
+		device:  device,
+		options: opts,
+		ready:   make(chan struct{}),
+	}
+}
+
+func (j *StreamJob) args() []string {
+	var args []string
+	if j.Upnp != nil {
+		args = append(args, "--upnp="+strconv.FormatBool(*j.Upnp))
+	}
+	if j.BindIp != "" {
+		args = append(args, "--bindip="+j.BindIp)
+	}
+	if j.BindPort != nil {
+		args = append(args, "--bindport="+strconv.Itoa(*j.BindPort))
+	}
+	return append(args, j.device.Type()+":"+j.device.Device())
+}
+
+// Run serves until the job is stopped or makemkvcon exits on its own, which
+// makes the error a StoppedError in the first case.
+func (j *StreamJob) Run() error {
+	return j.RunContext(context.Background())
+}
+
+func (j *StreamJob) RunContext(ctx context.Context) error {
+	defer j.stopper.watch(ctx)()
+	defer j.once.Do(func() { close(j.ready) })
+	if err := CheckAccess(j.device); err != nil {
+		return err
+	}
+	j.options.Power.begin(j.device, j.options)
+	defer j.options.Power.end(j.device)
+	opts, err := j.options.withProgress(false)
+	if err != nil {
+		return err
+	}
+	opts, file, cleanup, err := opts.withTransport()
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+	cmd := newCommand(opts, append([]string{"stream"}, j.args()...)...)
+
+	parseErr, err := runCommand(cmd, &j.stopper, file, opts.Audit, func(out io.Reader) error {
+		scanner := bufio.NewScanner(out)
+		for scanner.Scan() {
+			content, found := strings.CutPrefix(scanner.Text(), "MSG:")
+			if !found {
+				continue
+			}
+			if msg, ok := parseMessage(content); ok {
+				if u := findURL(msg.Text); u != "" {
+					j.mu.Lock()
+					j.url = u
+					j.mu.Unlock()
+					j.once.Do(func() { close(j.ready) })
+				}
+			}
+		}
+		return scanner.Err()
+	})
+	if err != nil {
+		return err
+	}
+	return parseErr
+}
+
+// Ready is closed once the server is up, or once Run has returned without
+// it ever coming up, in which case URL is empty
+func (j *StreamJob) Ready() <-chan struct{} {
+	return j.ready
+}
+
+// URL is the address makemkvcon announced for the server
+func (j *StreamJob) URL() string {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.url
+}
+
+// Port is the port from URL, 0 before the server is up
+func (j *StreamJob) Port() int {
+	u, err := url.Parse(j.URL())
+	if err != nil {
+		return 0
+	}
+	port, _ := strconv.Atoi(u.Port())
+	return port
+}
+
+func (j *StreamJob) Stop(reason StopReason) {
+	j.stopper.stop(reason)
+}
+
+func findURL(text string) string {
+	i := strings.Index(text, "http://")
+	if i < 0 {
+		if i = strings.Index(text, "https://"); i < 0 {
+			return ""
+		}
+	}
+	u := text[i:]
+	if end := strings.IndexAny(u, " \t'\""); end >= 0 {
+		u = u[:end]
+	}
+	return strings.TrimRight(u, ".,")
+}
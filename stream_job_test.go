@@ -0,0 +1,54 @@
+package makemkv
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aravance/go-makemkv/makemkvtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStreamJobArgs(t *testing.T) {
+	job := Serve(NewDiscDevice(1), MkvOptions{})
+	assert.Equal(t, []string{"disc:1"}, job.args())
+	job.Upnp = Ptr(true)
+	job.BindIp = "0.0.0.0"
+	job.BindPort = Ptr(51000)
+	assert.Equal(t, []string{"--upnp=true", "--bindip=0.0.0.0", "--bindport=51000", "disc:1"}, job.args())
+}
+
+func TestFindURL(t *testing.T) {
+	assert.Equal(t, "http://192.168.1.10:51000/", findURL("Server started at http://192.168.1.10:51000/"))
+	assert.Equal(t, "http://[::1]:8080", findURL("Listening on http://[::1]:8080."))
+	assert.Equal(t, "", findURL("Opening disc"))
+}
+
+func TestFakeServe(t *testing.T) {
+	opts := fakeMakemkvcon(t, `MSG:1005,0,1,"MakeMKV v1.17.6 linux(x64-release) started","%1 started","MakeMKV v1.17.6 linux(x64-release)"
+MSG:4500,0,1,"Server started at http://127.0.0.1:51000/","Server started at %1","http://127.0.0.1:51000/"
+`, 0)
+	opts.Env = append(opts.Env, makemkvtest.Hold(time.Minute))
+	job := Serve(NewIsoDevice("/disc.iso"), opts)
+	done := make(chan error, 1)
+	go func() { done <- job.Run() }()
+
+	select {
+	case <-job.Ready():
+	case <-time.After(30 * time.Second):
+		t.Fatal("server never came up")
+	}
+	assert.Equal(t, "http://127.0.0.1:51000/", job.URL())
+	assert.Equal(t, 51000, job.Port())
+
+	job.Stop(StopShutdown)
+	select {
+	case err := <-done:
+		var stopped *StoppedError
+		if assert.True(t, errors.As(err, &stopped)) {
+			assert.Equal(t, StopShutdown, stopped.Reason)
+		}
+	case <-time.After(30 * time.Second):
+		t.Fatal("stop didn't kill the server")
+	}
+}
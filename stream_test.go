@@ -0,0 +1,43 @@
+package makemkv
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTitleStreams(t *testing.T) {
+	scanner := bufio.NewScanner(strings.NewReader(`TCOUNT:1
+SINFO:0,0,1,6201,"Video"
+SINFO:0,0,6,0,"Mpeg4"
+SINFO:0,1,1,6203,"Subtitles"
+SINFO:0,1,3,0,"eng"
+SINFO:0,2,1,6202,"Audio"
+SINFO:0,2,3,0,"fra"
+SINFO:0,2,6,0,"AC3"
+SINFO:0,2,22,0,"1"
+SINFO:0,2,99,0,"future"
+`))
+	result, err := parseDiscInfo(scanner)
+	assert.Nil(t, err)
+
+	streams := result.Titles[0].Streams()
+	if assert.Equal(t, 3, len(streams)) {
+		assert.Equal(t, StreamVideo, streams[0].Kind())
+		assert.Equal(t, "Mpeg4", streams[0].Codec())
+		assert.Equal(t, "", streams[0].Language())
+		assert.Equal(t, StreamSubtitle, streams[1].Kind())
+		assert.Equal(t, "eng", streams[1].Language())
+		assert.Equal(t, 2, streams[2].StreamId())
+		assert.Equal(t, StreamAudio, streams[2].Kind())
+		assert.Equal(t, "fra", streams[2].Language())
+		assert.Equal(t, "AC3", streams[2].Codec())
+		assert.Equal(t, 1, streams[2].Flags())
+		if value, ok := streams[2].Attr(99); assert.True(t, ok) {
+			assert.Equal(t, "future", value)
+		}
+		assert.Equal(t, []int{1, 3, 6, 22, 99}, streams[2].AttrIds())
+	}
+}
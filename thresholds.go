@@ -0,0 +1,78 @@
+package makemkv
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Thresholds stop a rip with StopThreshold and a ThresholdError as soon as
+// makemkvcon reports more trouble than allowed. A nil limit is no limit.
+type Thresholds struct {
+	MaxReadErrors *int
+	// counted in files, however many times each of them fails
+	MaxHashFailures *int
+}
+
+type ThresholdError struct {
+	What  string
+	Limit int
+	Count int
+}
+
+func (e *ThresholdError) Error() string {
+	return fmt.Sprintf("makemkv: %d %s, more than the %d allowed", e.Count, e.What, e.Limit)
+}
+
+type thresholdWatch struct {
+	limits     Thresholds
+	readErrors int
+	hashFiles  map[string]bool
+	tripped    bool
+}
+
+// observe returns an error the first time a limit is exceeded
+func (w *thresholdWatch) observe(msg Message) error {
+	if w.tripped {
+		return nil
+	}
+	var err error
+	switch {
+	// "Error '%1' occurred while reading '%2' at offset '%3'"
+	case strings.Contains(msg.Format, "occurred while reading"):
+		w.readErrors++
+		if limit := w.limits.MaxReadErrors; limit != nil && w.readErrors > *limit {
+			err = &ThresholdError{What: "read errors", Limit: *limit, Count: w.readErrors}
+		}
+	// "Hash check failed for file %1 at offset %2, file is corrupt"
+	case strings.HasPrefix(msg.Format, "Hash check failed"):
+		if w.hashFiles == nil {
+			w.hashFiles = make(map[string]bool)
+		}
+		w.hashFiles[formatParam(msg, "file ")] = true
+		if limit := w.limits.MaxHashFailures; limit != nil && len(w.hashFiles) > *limit {
+			err = &ThresholdError{What: "files failing hash checks", Limit: *limit, Count: len(w.hashFiles)}
+		}
+	}
+	w.tripped = err != nil
+	return err
+}
+
+// formatParam returns the parameter whose placeholder directly follows
+// prefix in the message format, e.g. "file " for "... for file %2 ..."
+func formatParam(msg Message, prefix string) string {
+	i := strings.Index(msg.Format, prefix+"%")
+	if i < 0 {
+		return ""
+	}
+	digits := msg.Format[i+len(prefix)+1:]
+	end := 0
+	for end < len(digits) && digits[end] >= '0' && digits[end] <= '9' {
+		end++
+	}
+	n, err := strconv.Atoi(digits[:end])
+	if err != nil {
+		return ""
+	}
+	return msg.Param(n - 1)
+}
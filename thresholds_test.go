@@ -0,0 +1,33 @@
+package makemkv
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestThresholdWatch(t *testing.T) {
+	readError := Message{Format: "Error '%1' occurred while reading '%2' at offset '%3'", Params: []string{"Scsi error", "/BDMV/STREAM/00001.m2ts", "0"}}
+	hashFailure := func(file string) Message {
+		return Message{Format: "Hash check failed for file %1 at offset %2, file is corrupt", Params: []string{file, "0"}}
+	}
+
+	watch := thresholdWatch{limits: Thresholds{MaxReadErrors: Ptr(1)}}
+	assert.Nil(t, watch.observe(readError))
+	err := watch.observe(readError)
+	assert.Equal(t, &ThresholdError{What: "read errors", Limit: 1, Count: 2}, err)
+	assert.Nil(t, watch.observe(readError), "only reported once")
+
+	watch = thresholdWatch{limits: Thresholds{MaxHashFailures: Ptr(0)}}
+	assert.Equal(t, &ThresholdError{What: "files failing hash checks", Limit: 0, Count: 1}, watch.observe(hashFailure("00001.m2ts")))
+
+	watch = thresholdWatch{limits: Thresholds{MaxHashFailures: Ptr(1)}}
+	assert.Nil(t, watch.observe(hashFailure("00001.m2ts")))
+	assert.Nil(t, watch.observe(hashFailure("00001.m2ts")))
+	assert.NotNil(t, watch.observe(hashFailure("00002.m2ts")))
+
+	watch = thresholdWatch{}
+	for i := 0; i < 100; i++ {
+		assert.Nil(t, watch.observe(readError))
+	}
+}
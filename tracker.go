@@ -0,0 +1,66 @@
+package makemkv
+
+import "time"
+
+// StatusInfo is a status with what a UI usually wants to show derived from
+// it. ETA and BytesPerSecond stay zero until there is a rate to go on.
+type StatusInfo struct {
+	Status
+	TaskPercent    float64
+	TotalPercent   float64
+	Elapsed        time.Duration
+	ETA            time.Duration
+	BytesPerSecond float64
+}
+
+// how much each new rate sample counts for in the smoothed rate
+const rateSmoothing = 0.2
+
+// StatusTracker follows the statuses of one job. Size is how many bytes the
+// job writes, like the title's FileSize, zero when unknown.
+type StatusTracker struct {
+	Size int64
+
+	start    time.Time
+	last     time.Time
+	fraction float64
+	// smoothed fraction of the job done per second
+	rate float64
+}
+
+func NewStatusTracker(size int64) *StatusTracker {
+	return &StatusTracker{Size: size}
+}
+
+func (t *StatusTracker) Observe(status Status) StatusInfo {
+	return t.observeAt(status, time.Now())
+}
+
+func (t *StatusTracker) observeAt(status Status, now time.Time) StatusInfo {
+	info := StatusInfo{Status: status}
+	if status.Max <= 0 {
+		return info
+	}
+	info.TaskPercent = 100 * float64(status.Current) / float64(status.Max)
+	info.TotalPercent = 100 * float64(status.Total) / float64(status.Max)
+
+	fraction := float64(status.Total) / float64(status.Max)
+	if t.start.IsZero() {
+		t.start, t.last, t.fraction = now, now, fraction
+	}
+	if dt := now.Sub(t.last).Seconds(); dt > 0 && fraction >= t.fraction {
+		sample := (fraction - t.fraction) / dt
+		if t.rate == 0 {
+			t.rate = sample
+		} else {
+			t.rate += rateSmoothing * (sample - t.rate)
+		}
+		t.last, t.fraction = now, fraction
+	}
+	info.Elapsed = now.Sub(t.start)
+	if t.rate > 0 {
+		info.ETA = time.Duration((1 - fraction) / t.rate * float64(time.Second))
+		info.BytesPerSecond = t.rate * float64(t.Size)
+	}
+	return info
+}
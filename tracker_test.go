@@ -0,0 +1,31 @@
+package makemkv
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatusTracker(t *testing.T) {
+	tracker := NewStatusTracker(1000)
+	start := time.Unix(0, 0)
+
+	info := tracker.observeAt(Status{Current: 0, Total: 0, Max: 100}, start)
+	assert.Equal(t, 0.0, info.TotalPercent)
+	assert.Equal(t, time.Duration(0), info.ETA)
+
+	info = tracker.observeAt(Status{Current: 50, Total: 10, Max: 100}, start.Add(10*time.Second))
+	assert.Equal(t, 50.0, info.TaskPercent)
+	assert.Equal(t, 10.0, info.TotalPercent)
+	assert.Equal(t, 10*time.Second, info.Elapsed)
+	assert.Equal(t, 90*time.Second, info.ETA)
+	assert.InDelta(t, 10.0, info.BytesPerSecond, 0.001)
+
+	// a burst only moves the estimate part of the way
+	info = tracker.observeAt(Status{Current: 100, Total: 30, Max: 100}, start.Add(20*time.Second))
+	assert.InDelta(t, 0.012, tracker.rate, 0.0001)
+	assert.InDelta(t, (70 / 1.2 * float64(time.Second)), float64(info.ETA), float64(time.Millisecond))
+
+	assert.Equal(t, StatusInfo{Status: Status{Title: "x"}}, NewStatusTracker(0).Observe(Status{Title: "x"}))
+}
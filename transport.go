@@ -0,0 +1,169 @@
+package makemkv
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"time"
+)
+
+type Transport int
+
+const (
+	// TransportStdout reads robot output from makemkvcon's stdout.
+	TransportStdout Transport = iota
+	// TransportFile has makemkvcon write robot output to a temporary file
+	// which is tailed while the job runs, for platforms where stdout gets
+	// interleaved or garbled.
+	TransportFile
+)
+
+const tailInterval = 100 * time.Millisecond
+
+// withTransport points --messages at a temporary file when the options ask for
+// file transport, returning the file path to tail and a cleanup func. A
+// Profile is written out to a temporary file too, removed by the same func.
+func (m MkvOptions) withTransport() (MkvOptions, string, func(), error) {
+	m, cleanupProfile, err := m.withProfile()
+	if err != nil {
+		return m, "", nil, err
+	}
+	if m.Transport != TransportFile {
+		return m, "", cleanupProfile, nil
+	}
+	if !m.Messages.IsZero() {
+		cleanupProfile()
+		return m, "", nil, fmt.Errorf("%w: file transport sets --messages itself, got %q", ErrConflictingOptions, m.Messages)
+	}
+	f, err := os.CreateTemp("", "makemkv-*.log")
+	if err != nil {
+		cleanupProfile()
+		return m, "", nil, err
+	}
+	path := f.Name()
+	f.Close()
+	m.Messages = outputOf(path)
+	return m, path, func() { os.Remove(path); cleanupProfile() }, nil
+}
+
+func (m MkvOptions) withProfile() (MkvOptions, func(), error) {
+	if m.Profile == nil {
+		return m, func() {}, nil
+	}
+	if m.ProfileFile != "" {
+		return m, nil, fmt.Errorf("%w: both Profile and ProfileFile are set", ErrConflictingOptions)
+	}
+	f, err := os.CreateTemp("", "makemkv-*.mmcp.xml")
+	if err != nil {
+		return m, nil, err
+	}
+	path := f.Name()
+	if err := m.Profile.Write(f); err != nil {
+		f.Close()
+		os.Remove(path)
+		return m, nil, err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(path)
+		return m, nil, err
+	}
+	m.ProfileFile = path
+	return m, func() { os.Remove(path) }, nil
+}
+
+// runCommand starts cmd, hands its robot output to parse, and waits for it to
+// exit. err is the start or exit error after the stopper had its say.
+func runCommand(cmd *exec.Cmd, s *stopper, file string, audit AuditLog, parse func(io.Reader) error) (parseErr error, err error) {
+	if audit != nil {
+		start := time.Now()
+		defer func() {
+			audit.Record(auditEntry(cmd, start, err))
+		}()
+	}
+	if file == "" {
+		parseErr, err = runStdout(cmd, s, parse)
+	} else {
+		parseErr, err = runFile(cmd, s, file, parse)
+	}
+	return parseErr, s.finish(err)
+}
+
+// waitCommand waits for cmd to exit, then takes down anything makemkvcon
+// left running in its process group before reaping it. The exited leader
+// keeps the group id from being reused until then, and the stopper forgets
+// cmd first so a late stop can't signal a reused id either.
+func waitCommand(cmd *exec.Cmd, s *stopper) error {
+	if waitExited(cmd) {
+		killProcessGroup(cmd)
+		s.exited()
+	}
+	return cmd.Wait()
+}
+
+func runStdout(cmd *exec.Cmd, s *stopper, parse func(io.Reader) error) (error, error) {
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.start(cmd); err != nil {
+		return nil, err
+	}
+	parseErr := parse(out)
+	if parseErr != nil {
+		// keep makemkvcon from blocking on a full pipe so Wait can return
+		io.Copy(io.Discard, out)
+	}
+	return parseErr, waitCommand(cmd, s)
+}
+
+func runFile(cmd *exec.Cmd, s *stopper, file string, parse func(io.Reader) error) (error, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	if err := s.start(cmd); err != nil {
+		return nil, err
+	}
+
+	done := make(chan struct{})
+	var waitErr error
+	go func() {
+		waitErr = waitCommand(cmd, s)
+		close(done)
+	}()
+
+	parseErr := parse(&tailReader{f: f, done: done})
+	<-done
+	return parseErr, waitErr
+}
+
+// tailReader reads a file that is still being written, only reporting EOF
+// once done is closed and everything written before that has been read
+type tailReader struct {
+	f        *os.File
+	done     <-chan struct{}
+	finished bool
+}
+
+func (t *tailReader) Read(p []byte) (int, error) {
+	for {
+		n, err := t.f.Read(p)
+		if n > 0 {
+			return n, nil
+		}
+		if err != nil && err != io.EOF {
+			return 0, err
+		}
+		if t.finished {
+			return 0, io.EOF
+		}
+		select {
+		case <-t.done:
+			// one more pass to pick up whatever was written before exit
+			t.finished = true
+		case <-time.After(tailInterval):
+		}
+	}
+}
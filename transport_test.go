@@ -0,0 +1,53 @@
+package makemkv
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTailReader(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "messages.log")
+	w, err := os.Create(path)
+	assert.Nil(t, err)
+	defer w.Close()
+	r, err := os.Open(path)
+	assert.Nil(t, err)
+	defer r.Close()
+
+	done := make(chan struct{})
+	go func() {
+		w.WriteString("MSG:1005,0,1,\"started\"\n")
+		time.Sleep(2 * tailInterval)
+		w.WriteString("PRGV:1,2,3\n")
+		close(done)
+	}()
+
+	out, err := io.ReadAll(&tailReader{f: r, done: done})
+	assert.Nil(t, err)
+	assert.Equal(t, "MSG:1005,0,1,\"started\"\nPRGV:1,2,3\n", string(out))
+}
+
+func TestMkvOptionsWithTransport(t *testing.T) {
+	opts, file, cleanup, err := MkvOptions{}.withTransport()
+	assert.Nil(t, err)
+	assert.Equal(t, "", file)
+	assert.True(t, opts.Messages.IsZero())
+	cleanup()
+
+	opts, file, cleanup, err = MkvOptions{Transport: TransportFile}.withTransport()
+	assert.Nil(t, err)
+	assert.Equal(t, file, opts.Messages.String())
+	_, err = os.Stat(file)
+	assert.Nil(t, err)
+	cleanup()
+	_, err = os.Stat(file)
+	assert.True(t, os.IsNotExist(err))
+
+	_, _, _, err = MkvOptions{Transport: TransportFile, Messages: OutputStdout}.withTransport()
+	assert.ErrorIs(t, err, ErrConflictingOptions)
+}
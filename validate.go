@@ -0,0 +1,55 @@
+package makemkv
+
+import (
+	"fmt"
+	"time"
+)
+
+type TitleExpectations struct {
+	MinChapters int
+	MinDuration time.Duration
+	MaxDuration time.Duration
+	// the longest a chapter should average, a main feature split into a
+	// handful of hour long chapters is usually the wrong playlist
+	MaxAverageChapter time.Duration
+}
+
+var MainFeatureExpectations = TitleExpectations{
+	MinChapters:       5,
+	MinDuration:       60 * time.Minute,
+	MaxAverageChapter: 30 * time.Minute,
+}
+
+type TitleWarning struct {
+	TitleId int
+	Message string
+}
+
+func (w TitleWarning) String() string {
+	return fmt.Sprintf("title %d: %s", w.TitleId, w.Message)
+}
+
+// CheckTitle compares a title against expectations, zero valued expectations
+// are not checked.
+func CheckTitle(title TitleInfo, expect TitleExpectations) []TitleWarning {
+	var warnings []TitleWarning
+	warn := func(format string, args ...any) {
+		warnings = append(warnings, TitleWarning{TitleId: title.Id, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if expect.MinChapters > 0 && title.ChapterCount < expect.MinChapters {
+		warn("has %d chapters, expected at least %d", title.ChapterCount, expect.MinChapters)
+	}
+	if expect.MinDuration > 0 && title.Duration < expect.MinDuration {
+		warn("runs %s, expected at least %s", title.Duration, expect.MinDuration)
+	}
+	if expect.MaxDuration > 0 && title.Duration > expect.MaxDuration {
+		warn("runs %s, expected at most %s", title.Duration, expect.MaxDuration)
+	}
+	if expect.MaxAverageChapter > 0 && title.ChapterCount > 0 {
+		if average := title.Duration / time.Duration(title.ChapterCount); average > expect.MaxAverageChapter {
+			warn("chapters average %s, expected at most %s", average, expect.MaxAverageChapter)
+		}
+	}
+	return warnings
+}
@@ -0,0 +1,24 @@
+package makemkv
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckTitle(t *testing.T) {
+	good := TitleInfo{Id: 0, ChapterCount: 24, Duration: 2 * time.Hour}
+	assert.Nil(t, CheckTitle(good, MainFeatureExpectations))
+
+	bad := TitleInfo{Id: 3, ChapterCount: 2, Duration: 2 * time.Hour}
+	assert.Equal(t, []TitleWarning{
+		{TitleId: 3, Message: "has 2 chapters, expected at least 5"},
+		{TitleId: 3, Message: "chapters average 1h0m0s, expected at most 30m0s"},
+	}, CheckTitle(bad, MainFeatureExpectations))
+
+	short := TitleInfo{Id: 1, Duration: 10 * time.Minute}
+	assert.Equal(t, []TitleWarning{
+		{TitleId: 1, Message: "runs 10m0s, expected at least 20m0s"},
+	}, CheckTitle(short, TitleExpectations{MinDuration: 20 * time.Minute}))
+}
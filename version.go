@@ -0,0 +1,121 @@
+package makemkv
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Version is the makemkvcon version reported at startup. Output is parsed by
+// the protocol of the release it names, see protocols.
+type Version struct {
+	Major int
+	Minor int
+	Patch int
+	Beta  bool
+}
+
+func (v Version) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.Beta {
+		s += " beta"
+	}
+	return s
+}
+
+func (v Version) IsZero() bool {
+	return v == Version{}
+}
+
+// Supported reports whether v is a release this package knows the output
+// of, 1.16 or newer. Undetected versions aren't.
+func (v Version) Supported() bool {
+	return v.AtLeast(minVersion.Major, minVersion.Minor, minVersion.Patch)
+}
+
+func (v Version) AtLeast(major, minor, patch int) bool {
+	if v.Major != major {
+		return v.Major > major
+	}
+	if v.Minor != minor {
+		return v.Minor > minor
+	}
+	return v.Patch >= patch
+}
+
+// parseVersion reads a version out of the startup banner, which looks like
+// "MakeMKV v1.17.6 linux(x64-release) started"
+func parseVersion(banner string) (Version, bool) {
+	_, rest, found := strings.Cut(banner, "MakeMKV v")
+	if !found {
+		return Version{}, false
+	}
+	var v Version
+	if _, err := fmt.Sscanf(rest, "%d.%d.%d", &v.Major, &v.Minor, &v.Patch); err != nil {
+		return Version{}, false
+	}
+	v.Beta = strings.Contains(rest, "beta")
+	return v, true
+}
+
+// protocol is the robot output of a range of makemkvcon releases, as far as
+// this package parses it
+type protocol struct {
+	// the first release covered, up to the next entry's
+	since Version
+	// fields on DRV lines, the last being the device path
+	drvFields int
+	// fields on PRGT and PRGC lines
+	prgFields int
+	// fields on PRGV lines
+	prgvFields int
+}
+
+// protocols lists what releases print, oldest first. A release that changes
+// a field count gets an entry of its own. Releases newer than the last
+// entry, current betas included, are parsed by it, with any fields they add
+// at the end of a line counted as ParseReport.ExtraFields and ignored.
+var protocols = []protocol{
+	{since: Version{Major: 1, Minor: 16}, drvFields: 7, prgFields: 3, prgvFields: 3},
+}
+
+// the oldest release with a protocol
+var minVersion = protocols[0].since
+
+// protocolFor returns the protocol of release v. Undetected versions get the
+// newest one, older releases than any entry the oldest.
+func protocolFor(v Version) protocol {
+	if v.IsZero() {
+		return protocols[len(protocols)-1]
+	}
+	for i := len(protocols) - 1; i > 0; i-- {
+		if since := protocols[i].since; v.AtLeast(since.Major, since.Minor, since.Patch) {
+			return protocols[i]
+		}
+	}
+	return protocols[0]
+}
+
+// fields returns how many fields lines of kind have, 0 for lines whose
+// field count varies
+func (p protocol) fields(kind linePrefix) int {
+	switch kind {
+	case prefixDRV:
+		return p.drvFields
+	case prefixPRGT, prefixPRGC:
+		return p.prgFields
+	case prefixPRGV:
+		return p.prgvFields
+	}
+	return 0
+}
+
+// field returns the i-th comma separated field, or an empty string when an
+// older or newer makemkvcon emits fewer fields than expected
+func field(parts []string, i int) string {
+	if i < len(parts) {
+		return parts[i]
+	}
+	return ""
+}
+
+const msgStarted = 1005
@@ -0,0 +1,88 @@
+package makemkv
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseVersion(t *testing.T) {
+	v, ok := parseVersion("MakeMKV v1.17.6 linux(x64-release) started")
+	assert.True(t, ok)
+	assert.Equal(t, Version{Major: 1, Minor: 17, Patch: 6}, v)
+	assert.True(t, v.AtLeast(1, 16, 0))
+	assert.True(t, v.AtLeast(1, 17, 6))
+	assert.False(t, v.AtLeast(1, 17, 7))
+	assert.False(t, v.AtLeast(2, 0, 0))
+
+	v, ok = parseVersion("MakeMKV v1.18.0 beta linux(x64-release) started")
+	assert.True(t, ok)
+	assert.Equal(t, "1.18.0 beta", v.String())
+
+	_, ok = parseVersion("Using direct disc access mode")
+	assert.False(t, ok)
+}
+
+// robot output of a short rip as printed by each release, the beta adding a
+// field to DRV and PRGV lines
+var releaseOutputs = map[string]string{
+	"1.16.5": `MSG:1005,0,1,"MakeMKV v1.16.5 linux(x64-release) started","%1 started","MakeMKV v1.16.5 linux(x64-release)"
+DRV:0,2,999,1,"BD-RE HL-DT-ST BD-RE  WH16NS60 1.02","MOVIE","/dev/sr0"
+PRGT:5018,0,"Saving to MKV file"
+PRGC:5017,0,"Saving to MKV file"
+PRGV:32768,32768,65536
+`,
+	"1.17.7": `MSG:1005,0,1,"MakeMKV v1.17.7 linux(x64-release) started","%1 started","MakeMKV v1.17.7 linux(x64-release)"
+DRV:0,2,999,1,"BD-RE HL-DT-ST BD-RE  WH16NS60 1.02","MOVIE","/dev/sr0"
+PRGT:5018,0,"Saving to MKV file"
+PRGC:5017,0,"Saving to MKV file"
+PRGV:32768,32768,65536
+`,
+	"1.18.2 beta": `MSG:1005,0,1,"MakeMKV v1.18.2 beta linux(x64-release) started","%1 started","MakeMKV v1.18.2 beta linux(x64-release)"
+DRV:0,2,999,1,"BD-RE HL-DT-ST BD-RE  WH16NS60 1.02","MOVIE","/dev/sr0","sata"
+PRGT:5018,0,"Saving to MKV file"
+PRGC:5017,0,"Saving to MKV file"
+PRGV:32768,32768,65536,12
+`,
+}
+
+func TestReleaseOutputs(t *testing.T) {
+	for release, output := range releaseOutputs {
+		var statuses []Status
+		parser := progressParser{status: func(status Status) {
+			statuses = append(statuses, status)
+		}}
+		for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+			parser.line(line)
+		}
+		assert.Equal(t, release, parser.version.String())
+		assert.True(t, parser.version.Supported(), release)
+		assert.Equal(t, 0, parser.report.Malformed, release)
+		if parser.version.Beta {
+			assert.Equal(t, 2, parser.report.ExtraFields, release)
+		} else {
+			assert.Equal(t, 0, parser.report.ExtraFields, release)
+		}
+		if assert.Equal(t, 1, len(parser.summary.drives), release) {
+			assert.Equal(t, "/dev/sr0", parser.summary.drives[0].DevicePath, release)
+		}
+		if assert.Equal(t, 1, len(statuses), release) {
+			assert.Equal(t, Status{Title: "Saving to MKV file", Channel: "Saving to MKV file", TitleCode: 5018, ChannelCode: 5017, Current: 32768, Total: 32768, Max: 65536, Seq: 1}, statuses[0], release)
+		}
+	}
+}
+
+func TestProtocolFor(t *testing.T) {
+	assert.Equal(t, protocols[len(protocols)-1], protocolFor(Version{}))
+	assert.Equal(t, protocols[0], protocolFor(Version{Major: 1, Minor: 16}))
+	assert.Equal(t, protocols[len(protocols)-1], protocolFor(Version{Major: 1, Minor: 99}))
+	assert.False(t, Version{Major: 1, Minor: 15, Patch: 4}.Supported())
+	assert.False(t, Version{}.Supported())
+
+	var parser progressParser
+	parser.line(`DRV:0,2,999,1,"BD-RE HL-DT-ST BD-RE  WH16NS60 1.02","MOVIE"`)
+	parser.line(`PRGV:32768,65536`)
+	assert.Equal(t, 2, parser.report.Malformed, "short lines")
+	assert.Empty(t, parser.summary.drives)
+}